@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yandex/rdsync/internal/app"
+)
+
+var budgetResetReason string
+
+var budgetCmd = &cobra.Command{
+	Use:   "budget",
+	Short: "Shows automatic failover budget usage",
+	Long: "Automatic failovers are limited by failover_budget within failover_budget_window" +
+		" and, with failover_backoff, by cooldown doubled after each recent failover.",
+	Run: func(cmd *cobra.Command, args []string) {
+		app, err := app.NewApp(configFile, logLevel)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		code := app.CliGetFailoverBudget()
+		app.CloseLogger()
+		os.Exit(code)
+	},
+}
+
+var budgetResetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Forgets previous automatic failovers, e.g. after incident is resolved",
+	Run: func(cmd *cobra.Command, args []string) {
+		app, err := app.NewApp(configFile, logLevel)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		code := app.CliResetFailoverBudget(budgetResetReason)
+		app.CloseLogger()
+		os.Exit(code)
+	},
+}
+
+func init() {
+	budgetResetCmd.Flags().StringVarP(&budgetResetReason, "reason", "r", "", "why budget is reset")
+	budgetCmd.AddCommand(budgetResetCmd)
+	rootCmd.AddCommand(budgetCmd)
+}
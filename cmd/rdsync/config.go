@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yandex/rdsync/internal/app"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Effective configuration management",
+}
+
+var configDumpCmd = &cobra.Command{
+	Use:   "dump",
+	Short: "Print effective config with secrets redacted",
+	Long: "Effective config is built from defaults, config file, drop-in directory," +
+		" RDSYNC_* environment variables and *_file secrets.",
+	Run: func(cmd *cobra.Command, args []string) {
+		app, err := app.NewApp(configFile, logLevel)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		code := app.CliConfigDump()
+		app.CloseLogger()
+		os.Exit(code)
+	},
+}
+
+var reloadWait time.Duration
+
+var configReloadCmd = &cobra.Command{
+	Use:   "reload",
+	Short: "Reload config of running rdsync daemon",
+	Long: "Same as sending SIGHUP. Safe changes (timeouts, log level, credentials, tls)" +
+		" are applied live, others are reported as requiring restart.",
+	Run: func(cmd *cobra.Command, args []string) {
+		app, err := app.NewApp(configFile, logLevel)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		code := app.CliConfigReload(reloadWait)
+		app.CloseLogger()
+		os.Exit(code)
+	},
+}
+
+var configShard bool
+
+var configGetCmd = &cobra.Command{
+	Use:   "get [key]",
+	Short: "Print effective config value",
+	Long: "Without key all shard-wide settings are printed." +
+		" With --shard only overrides stored in DCS are shown.",
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		app, err := app.NewApp(configFile, logLevel)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		key := ""
+		if len(args) > 0 {
+			key = args[0]
+		}
+		code := app.CliConfigGet(key, configShard)
+		app.CloseLogger()
+		os.Exit(code)
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set --shard <key> <value>",
+	Short: "Set shard-wide config value in DCS",
+	Long:  "Shard-wide value overrides local one on all hosts except ones listing the key in pinned_local_settings.",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if !configShard {
+			fmt.Println("only shard-wide values could be set, use --shard")
+			os.Exit(1)
+		}
+		app, err := app.NewApp(configFile, logLevel)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		code := app.CliConfigSet(args[0], args[1])
+		app.CloseLogger()
+		os.Exit(code)
+	},
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset --shard <key>",
+	Short: "Remove shard-wide config value from DCS",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if !configShard {
+			fmt.Println("only shard-wide values could be unset, use --shard")
+			os.Exit(1)
+		}
+		app, err := app.NewApp(configFile, logLevel)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		code := app.CliConfigUnset(args[0])
+		app.CloseLogger()
+		os.Exit(code)
+	},
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{configGetCmd, configSetCmd, configUnsetCmd} {
+		cmd.Flags().BoolVar(&configShard, "shard", false, "operate on shard-wide settings stored in DCS")
+		configCmd.AddCommand(cmd)
+	}
+	configReloadCmd.Flags().DurationVarP(&reloadWait, "wait", "w", 30*time.Second,
+		"how long to wait for reload result, 0s to return immediately")
+	configCmd.AddCommand(configDumpCmd)
+	configCmd.AddCommand(configReloadCmd)
+	rootCmd.AddCommand(configCmd)
+}
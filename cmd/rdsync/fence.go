@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yandex/rdsync/internal/app"
+)
+
+var (
+	fenceReason string
+	fenceTTL    time.Duration
+	fenceForce  bool
+)
+
+var fenceCmd = &cobra.Command{
+	Use:   "fence <host> [<host>...]",
+	Short: "Makes shard members go offline via poison pill",
+	Long: "Fenced hosts drop client connections and stay offline until unfenced or fence expiry." +
+		" Fencing current master causes failover.",
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		app, err := app.NewApp(configFile, logLevel)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		code := app.CliFence(args, fenceReason, fenceTTL, fenceForce)
+		app.CloseLogger()
+		os.Exit(code)
+	},
+}
+
+var unfenceCmd = &cobra.Command{
+	Use:   "unfence <host> [<host>...]",
+	Short: "Removes manual fence allowing hosts to go online",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		app, err := app.NewApp(configFile, logLevel)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		code := app.CliUnfence(args)
+		app.CloseLogger()
+		os.Exit(code)
+	},
+}
+
+func init() {
+	fenceCmd.Flags().StringVarP(&fenceReason, "reason", "r", "", "why hosts are fenced")
+	fenceCmd.Flags().DurationVar(&fenceTTL, "ttl", 0, "fence expiry, 0s to keep it until unfenced")
+	fenceCmd.Flags().BoolVar(&fenceForce, "force", false, "allow fencing current master")
+	rootCmd.AddCommand(fenceCmd)
+	rootCmd.AddCommand(unfenceCmd)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yandex/rdsync/internal/app"
+)
+
+var (
+	freezeReason     string
+	freezeDuration   time.Duration
+	freezeSwitchover bool
+)
+
+var freezeCmd = &cobra.Command{
+	Use:   "freeze",
+	Short: "Enables or disables automatic failover freeze",
+	Long: "When failover is frozen RdSync keeps repairing replicas and managing quorum" +
+		" but does not perform automatic failover.",
+	Run: func(cmd *cobra.Command, args []string) {
+		app, err := app.NewApp(configFile, logLevel)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		code := app.CliGetFailoverFreeze()
+		app.CloseLogger()
+		os.Exit(code)
+	},
+}
+
+var freezeOnCmd = &cobra.Command{
+	Use:     "on",
+	Aliases: []string{"enable"},
+	Run: func(cmd *cobra.Command, args []string) {
+		app, err := app.NewApp(configFile, logLevel)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		code := app.CliEnableFailoverFreeze(freezeReason, freezeDuration, freezeSwitchover)
+		app.CloseLogger()
+		os.Exit(code)
+	},
+}
+
+var freezeOffCmd = &cobra.Command{
+	Use:     "off",
+	Aliases: []string{"disable"},
+	Run: func(cmd *cobra.Command, args []string) {
+		app, err := app.NewApp(configFile, logLevel)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		code := app.CliDisableFailoverFreeze()
+		app.CloseLogger()
+		os.Exit(code)
+	},
+}
+
+func init() {
+	freezeOnCmd.Flags().StringVarP(&freezeReason, "reason", "r", "", "why failover is frozen")
+	freezeOnCmd.Flags().DurationVarP(&freezeDuration, "duration", "d", time.Hour,
+		"freeze expiry, 0s to keep it until explicitly disabled")
+	freezeOnCmd.Flags().BoolVar(&freezeSwitchover, "switchover", false, "forbid manual switchover too")
+	freezeCmd.AddCommand(freezeOnCmd)
+	freezeCmd.AddCommand(freezeOffCmd)
+	rootCmd.AddCommand(freezeCmd)
+}
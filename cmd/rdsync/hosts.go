@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -13,6 +14,16 @@ import (
 var priority int
 var dryRun bool
 var skipValkeyCheck bool
+var hostMaintReason string
+var removeForce bool
+var drainWait time.Duration
+var join bool
+var importPrune bool
+var drained bool
+var neverPromote bool
+var nonVoting bool
+var zone string
+var joinWait time.Duration
 
 var hostListCmd = &cobra.Command{
 	Use:     "host",
@@ -49,7 +60,7 @@ var hostAddCmd = &cobra.Command{
 			}
 		})
 
-		code := app.CliHostAdd(args[0], priorityVal, dryRun, skipValkeyCheck)
+		code := app.CliHostAdd(args[0], priorityVal, dryRun, skipValkeyCheck, join, joinWait)
 		app.CloseLogger()
 		os.Exit(code)
 	},
@@ -65,7 +76,167 @@ var hostRemoveCmd = &cobra.Command{
 			fmt.Println(err)
 			os.Exit(1)
 		}
-		code := app.CliHostRemove(args[0])
+		code := app.CliHostRemove(args[0], removeForce, dryRun)
+		app.CloseLogger()
+		os.Exit(code)
+	},
+}
+
+var hostMaintCmd = &cobra.Command{
+	Use:     "maintenance",
+	Aliases: []string{"maint", "mnt"},
+	Short:   "enables or disables maintenance of a single host",
+	Long: "Host under maintenance is not repaired, not promoted, not counted in quorum" +
+		" and its rdsync does not take manager lock.",
+}
+
+var hostMaintOnCmd = &cobra.Command{
+	Use:     "on",
+	Aliases: []string{"enable"},
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		app, err := app.NewApp(configFile, logLevel)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		code := app.CliHostMaintenance(args[0], true, hostMaintReason)
+		app.CloseLogger()
+		os.Exit(code)
+	},
+}
+
+var hostMaintOffCmd = &cobra.Command{
+	Use:     "off",
+	Aliases: []string{"disable"},
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		app, err := app.NewApp(configFile, logLevel)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		code := app.CliHostMaintenance(args[0], false, "")
+		app.CloseLogger()
+		os.Exit(code)
+	},
+}
+
+var hostDrainCmd = &cobra.Command{
+	Use:   "drain",
+	Short: "take host out of service",
+	Long: "Marks host non-promotable, switches master away from it and waits for it to leave active nodes." +
+		" Refuses to drain if shard loses failover quorum without the host.",
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		app, err := app.NewApp(configFile, logLevel)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		code := app.CliHostDrain(args[0], drainWait)
+		app.CloseLogger()
+		os.Exit(code)
+	},
+}
+
+var hostUndrainCmd = &cobra.Command{
+	Use:   "undrain",
+	Short: "return drained host to service",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		app, err := app.NewApp(configFile, logLevel)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		code := app.CliHostUndrain(args[0])
+		app.CloseLogger()
+		os.Exit(code)
+	},
+}
+
+var hostShowCmd = &cobra.Command{
+	Use:   "show [host]",
+	Short: "show configuration and health of hosts",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		app, err := app.NewApp(configFile, logLevel)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		host := ""
+		if len(args) > 0 {
+			host = args[0]
+		}
+		code := app.CliHostShow(host)
+		app.CloseLogger()
+		os.Exit(code)
+	},
+}
+
+var hostExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "print configuration of all hosts as yaml",
+	Run: func(cmd *cobra.Command, args []string) {
+		app, err := app.NewApp(configFile, logLevel)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		code := app.CliHostExport()
+		app.CloseLogger()
+		os.Exit(code)
+	},
+}
+
+var hostImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "apply configuration of hosts from yaml document (- for stdin)",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		app, err := app.NewApp(configFile, logLevel)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		code := app.CliHostImport(args[0], importPrune, dryRun, skipValkeyCheck)
+		app.CloseLogger()
+		os.Exit(code)
+	},
+}
+
+var hostSetCmd = &cobra.Command{
+	Use:   "set <host>",
+	Short: "change configuration fields of a host",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		app, err := app.NewApp(configFile, logLevel)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		var priorityVal *int
+		var drainedVal, neverPromoteVal, nonVotingVal *bool
+		var zoneVal *string
+		cmd.Flags().Visit(func(f *pflag.Flag) {
+			switch f.Name {
+			case "priority":
+				priorityVal = &priority
+			case "drained":
+				drainedVal = &drained
+			case "never-promote":
+				neverPromoteVal = &neverPromote
+			case "non-voting":
+				nonVotingVal = &nonVoting
+			case "zone":
+				zoneVal = &zone
+			}
+		})
+
+		code := app.CliHostSet(args[0], priorityVal, drainedVal, neverPromoteVal, nonVotingVal, zoneVal)
 		app.CloseLogger()
 		os.Exit(code)
 	},
@@ -79,7 +250,43 @@ func init() {
 		" 0 - when no changes detected,"+
 		" 1 - when some error happened or changes prohibited,"+
 		" 2 - when changes detected and some changes will be performed during usual run")
+	hostAddCmd.Flags().BoolVar(&join, "join", false,
+		"add host as replica only, making it eligible for active nodes and promotion after it finishes sync")
+	hostAddCmd.Flags().DurationVarP(&joinWait, "wait", "w", 30*time.Minute,
+		"how long to wait for join completion, 0s to return immediately")
+	hostRemoveCmd.Flags().BoolVar(&removeForce, "force", false,
+		"remove host even if it is current master or shard loses quorum without it")
+	hostRemoveCmd.Flags().BoolVar(&dryRun, "dry-run", false, "tests suggested changes."+
+		" Exits codes:"+
+		" 0 - when host is not in shard,"+
+		" 1 - when some error happened or removal prohibited,"+
+		" 2 - when host will be removed")
+	hostImportCmd.Flags().BoolVar(&importPrune, "prune", false, "remove hosts missing in document")
+	hostImportCmd.Flags().BoolVar(&skipValkeyCheck, "skip-valkey-check", false, "do not check valkey availability of new hosts")
+	hostImportCmd.Flags().BoolVar(&dryRun, "dry-run", false, "show changes only."+
+		" Exits codes:"+
+		" 0 - when no changes detected,"+
+		" 1 - when some error happened or changes prohibited,"+
+		" 2 - when changes detected")
+	hostSetCmd.Flags().IntVar(&priority, "priority", 100, "host priority")
+	hostSetCmd.Flags().BoolVar(&drained, "drained", false, "mark host as drained (no quorum checks, see drain command)")
+	hostSetCmd.Flags().BoolVar(&neverPromote, "never-promote", false, "forbid host to become master")
+	hostSetCmd.Flags().BoolVar(&nonVoting, "non-voting", false,
+		"do not count host in replicas to write and failover quorum")
+	hostSetCmd.Flags().StringVar(&zone, "zone", "", "availability zone of host, empty to unset")
 	hostListCmd.AddCommand(hostAddCmd)
+	hostListCmd.AddCommand(hostShowCmd)
+	hostListCmd.AddCommand(hostExportCmd)
+	hostListCmd.AddCommand(hostImportCmd)
+	hostListCmd.AddCommand(hostSetCmd)
 	hostListCmd.AddCommand(hostRemoveCmd)
+	hostMaintOnCmd.Flags().StringVarP(&hostMaintReason, "reason", "r", "", "why host is put into maintenance")
+	hostMaintCmd.AddCommand(hostMaintOnCmd)
+	hostMaintCmd.AddCommand(hostMaintOffCmd)
+	hostListCmd.AddCommand(hostMaintCmd)
+	hostDrainCmd.Flags().DurationVarP(&drainWait, "wait", "w", 5*time.Minute,
+		"how long to wait for drain completion, 0s to return immediately")
+	hostListCmd.AddCommand(hostDrainCmd)
+	hostListCmd.AddCommand(hostUndrainCmd)
 	rootCmd.AddCommand(hostListCmd)
 }
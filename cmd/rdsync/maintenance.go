@@ -10,7 +10,11 @@ import (
 	"github.com/yandex/rdsync/internal/app"
 )
 
-var maintWait time.Duration
+var (
+	maintWait   time.Duration
+	maintReason string
+	maintTTL    time.Duration
+)
 
 var maintCmd = &cobra.Command{
 	Use:     "maintenance",
@@ -38,7 +42,7 @@ var maintOnCmd = &cobra.Command{
 			fmt.Println(err)
 			os.Exit(1)
 		}
-		code := app.CliEnableMaintenance(maintWait)
+		code := app.CliEnableMaintenance(maintWait, maintReason, maintTTL)
 		app.CloseLogger()
 		os.Exit(code)
 	},
@@ -60,6 +64,9 @@ var maintOffCmd = &cobra.Command{
 }
 
 func init() {
+	maintOnCmd.Flags().StringVarP(&maintReason, "reason", "r", "", "why shard is put into maintenance")
+	maintOnCmd.Flags().DurationVar(&maintTTL, "ttl", 0,
+		"leave maintenance automatically after this duration, 0s to keep it until explicitly disabled")
 	maintCmd.AddCommand(maintOnCmd)
 	maintCmd.AddCommand(maintOffCmd)
 	maintCmd.PersistentFlags().DurationVarP(&maintWait, "wait", "w", 30*time.Second,
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/yandex/rdsync/internal/app"
+)
+
+var (
+	scheduleAt     string
+	scheduleWindow string
+	scheduleFrom   string
+	scheduleTo     string
+	scheduleReason string
+)
+
+var scheduleCmd = &cobra.Command{
+	Use:     "schedule",
+	Aliases: []string{"scheduled"},
+	Short:   "Lists operations scheduled for future time or recurring window",
+	Run: func(cmd *cobra.Command, args []string) {
+		app, err := app.NewApp(configFile, logLevel)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		code := app.CliScheduleList()
+		app.CloseLogger()
+		os.Exit(code)
+	},
+}
+
+var scheduleAddCmd = &cobra.Command{
+	Use:   "add <switchover|maintenance_on|maintenance_off>",
+	Short: "Schedules operation to be executed by manager",
+	Long: "Operation is executed once at --at time or daily within --window." +
+		" Manager refuses to execute it if shard is unhealthy at that time." +
+		" One-shot operation not executed within 15 minutes after --at is marked as missed." +
+		" Maintenance enabled within --window is left at the window end.",
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		app, err := app.NewApp(configFile, logLevel)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		code := app.CliScheduleAdd(args[0], scheduleAt, scheduleWindow, scheduleFrom, scheduleTo, scheduleReason)
+		app.CloseLogger()
+		os.Exit(code)
+	},
+}
+
+var scheduleCancelCmd = &cobra.Command{
+	Use:   "cancel <id>",
+	Short: "Cancels scheduled operation",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		app, err := app.NewApp(configFile, logLevel)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		code := app.CliScheduleCancel(args[0])
+		app.CloseLogger()
+		os.Exit(code)
+	},
+}
+
+func init() {
+	scheduleAddCmd.Flags().StringVar(&scheduleAt, "at", "", "execution time in RFC3339 format")
+	scheduleAddCmd.Flags().StringVar(&scheduleWindow, "window", "", "daily window in HH:MM-HH:MM format (local time)")
+	scheduleAddCmd.Flags().StringVar(&scheduleFrom, "from", "", "switch master from specific host")
+	scheduleAddCmd.Flags().StringVar(&scheduleTo, "to", "", "switch master to specific host")
+	scheduleAddCmd.Flags().StringVarP(&scheduleReason, "reason", "r", "", "why operation is scheduled")
+	scheduleCmd.AddCommand(scheduleAddCmd)
+	scheduleCmd.AddCommand(scheduleCancelCmd)
+	rootCmd.AddCommand(scheduleCmd)
+}
@@ -14,6 +14,11 @@ var switchTo string
 var switchFrom string
 var switchWait time.Duration
 var switchForce bool
+var switchPlan bool
+var switchIgnoreLag bool
+var switchIgnoreSync bool
+var switchIgnorePsync bool
+var switchIgnoreRecentFailure bool
 
 var switchCmd = &cobra.Command{
 	Use:   "switch",
@@ -25,7 +30,20 @@ var switchCmd = &cobra.Command{
 			fmt.Println(err)
 			os.Exit(1)
 		}
-		code := app.CliSwitch(switchFrom, switchTo, switchWait, switchForce)
+		var ignoredChecks []string
+		if switchIgnoreLag {
+			ignoredChecks = append(ignoredChecks, "lag")
+		}
+		if switchIgnoreSync {
+			ignoredChecks = append(ignoredChecks, "sync")
+		}
+		if switchIgnorePsync {
+			ignoredChecks = append(ignoredChecks, "psync")
+		}
+		if switchIgnoreRecentFailure {
+			ignoredChecks = append(ignoredChecks, "recent_failure")
+		}
+		code := app.CliSwitch(switchFrom, switchTo, switchWait, switchForce, switchPlan, ignoredChecks)
 		app.CloseLogger()
 		os.Exit(code)
 	},
@@ -36,6 +54,11 @@ func init() {
 	switchCmd.Flags().StringVar(&switchFrom, "from", "", "switch master from specific (or current master if empty) host")
 	switchCmd.Flags().StringVar(&switchTo, "to", "", "switch master to specific (or most up-to-date if empty) host")
 	switchCmd.Flags().BoolVar(&switchForce, "force", false, "make switchover preapproved")
+	switchCmd.Flags().BoolVar(&switchPlan, "plan", false, "print expected switchover result without modifying anything, exit with non-zero code if it would be refused")
+	switchCmd.Flags().BoolVar(&switchIgnoreLag, "ignore-lag", false, "ignore switchover target lag precondition")
+	switchCmd.Flags().BoolVar(&switchIgnoreSync, "ignore-sync", false, "ignore full sync in progress precondition")
+	switchCmd.Flags().BoolVar(&switchIgnorePsync, "ignore-psync", false, "ignore replicas psync from target precondition")
+	switchCmd.Flags().BoolVar(&switchIgnoreRecentFailure, "ignore-recent-failure", false, "ignore recent failed switchover precondition")
 	switchCmd.Flags().DurationVarP(&switchWait, "wait", "w", 5*time.Minute,
 		"how long wait for switchover to complete, 0s to return immediately")
 }
@@ -29,13 +29,13 @@ func (app *App) actualizeQuorumReplicas(master string, activeNodes []string) err
 	var expected []string
 
 	for _, host := range activeNodes {
-		if host == master {
+		if host == master || !app.isHostVoting(host) {
 			continue
 		}
 		activeNode := app.shard.Get(host)
-		expected = append(expected, fmt.Sprintf("%s:%d", host, app.config.Valkey.Port))
+		expected = append(expected, fmt.Sprintf("%s:%d", host, app.config().Valkey.Port))
 		for _, ip := range activeNode.GetIPs() {
-			expected = append(expected, fmt.Sprintf("%s:%d", ip, app.config.Valkey.Port))
+			expected = append(expected, fmt.Sprintf("%s:%d", ip, app.config().Valkey.Port))
 		}
 	}
 
@@ -162,12 +162,17 @@ func applyActiveNodesTransition(oldActiveNodes, activeNodes []string, actualNumR
 
 func (app *App) updateActiveNodes(state, stateDcs map[string]*HostState, oldActiveNodes []string, master string) error {
 	activeNodes := app.calcActiveNodes(state, stateDcs, oldActiveNodes, master)
+	return app.transitionActiveNodes(oldActiveNodes, activeNodes, master)
+}
+
+// transitionActiveNodes changes active nodes in dcs and quorum settings on master without write unavailability
+func (app *App) transitionActiveNodes(oldActiveNodes, activeNodes []string, master string) error {
 	masterNode := app.shard.Get(master)
 	actualNumReplicas, err := masterNode.GetNumQuorumReplicas(app.ctx)
 	if err != nil {
 		return fmt.Errorf("get num quorum replicas on master: %w", err)
 	}
-	expectedNumReplicas := app.getNumReplicasToWrite(activeNodes)
+	expectedNumReplicas := app.getNumReplicasToWrite(activeNodes, master)
 
 	ops := activeNodesTransitionOps{
 		setQuorumReplicas: func(nodes []string) error {
@@ -215,6 +220,10 @@ func (app *App) calcActiveNodes(state, stateDcs map[string]*HostState, oldActive
 		if host == master {
 			continue
 		}
+		if reason := app.ineligibleReason(host); reason != "" {
+			app.logger.Info().Msgf("Calc active nodes: %s is %s, skipping", host, reason)
+			continue
+		}
 		if !node.PingOk {
 			if stateDcs[host].PingOk {
 				if slices.Contains(oldActiveNodes, host) {
@@ -227,9 +236,9 @@ func (app *App) calcActiveNodes(state, stateDcs map[string]*HostState, oldActive
 				app.nodeFailTime[host] = time.Now()
 			}
 			failTime := time.Since(app.nodeFailTime[host])
-			if failTime < app.config.InactivationDelay {
+			if failTime < app.config().InactivationDelay {
 				if slices.Contains(oldActiveNodes, host) {
-					app.logger.Warn().Msgf("Calc active nodes: %s is failing, remaining %v", host, app.config.InactivationDelay-failTime)
+					app.logger.Warn().Msgf("Calc active nodes: %s is failing, remaining %v", host, app.config().InactivationDelay-failTime)
 					activeNodes = append(activeNodes, host)
 				}
 				continue
@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"os/signal"
+	"strconv"
 	"sync/atomic"
 	"syscall"
 	"time"
@@ -20,24 +21,37 @@ import (
 
 // App is main application structure
 type App struct {
-	dcsDivergeTime time.Time
-	replFailTime   time.Time
-	lostSince      time.Time
-	critical       atomic.Value
-	ctx            context.Context
-	dcs            dcs.DCS
-	config         *config.Config
-	splitTime      map[string]time.Time
-	logger         *zerolog.Logger
-	loggerCloser   io.Closer
-	nodeFailTime   map[string]time.Time
-	shard          *valkey.Shard
-	cache          *valkey.SentiCacheNode
-	daemonLock     *flock.Flock
-	timings        *TimingReporter
-	mode           appMode
-	aofMode        aofMode
-	state          appState
+	dcsDivergeTime       time.Time
+	replFailTime         time.Time
+	lostSince            time.Time
+	switchBackSince      time.Time
+	maintenanceWarnedAt  time.Time
+	maintenanceWarnedFor time.Time
+	critical             atomic.Value
+	ctx                  context.Context
+	dcs                  dcs.DCS
+	configHolder         *config.Holder
+	localConfig          *config.Config
+	shardConfig          map[string]string
+	settingsDivergence   map[string]string
+	joinStages           map[string]string
+	splitTime            map[string]time.Time
+	logger               *zerolog.Logger
+	loggerCloser         io.Closer
+	logLevel             *levelFilterWriter
+	reloadRequests       chan chan ConfigReloadResult
+	nodeFailTime         map[string]time.Time
+	nodeConfigs          map[string]*valkey.NodeConfiguration
+	failoverVerification *failoverVerification
+	shard                *valkey.Shard
+	cache                *valkey.SentiCacheNode
+	daemonLock           *flock.Flock
+	timings              *TimingReporter
+	configFile           string
+	switchBackHost       string
+	mode                 appMode
+	aofMode              aofMode
+	state                appState
 }
 
 func baseContext() context.Context {
@@ -65,33 +79,47 @@ func NewApp(configFile, logLevel string) (*App, error) {
 	if err != nil {
 		return nil, err
 	}
-	logger, loggerCloser := newMainLogger(logLevelN, conf.LogBufferSize, conf.LogPollInterval)
+	logger, loggerCloser, logLevelWriter := newMainLogger(logLevelN, conf.LogBufferSize, conf.LogPollInterval)
 	mode, err := parseMode(conf.Mode)
 	if err != nil {
 		return nil, err
 	}
+	if err = validateConfig(conf); err != nil {
+		return nil, err
+	}
 	aofMode, err := parseAofMode(conf.AofMode)
 	if err != nil {
 		return nil, err
 	}
+	localConf := *conf
 	app := &App{
-		ctx:          baseContext(),
-		mode:         mode,
-		aofMode:      aofMode,
-		nodeFailTime: make(map[string]time.Time),
-		splitTime:    make(map[string]time.Time),
-		state:        stateInit,
-		logger:       logger,
-		loggerCloser: loggerCloser,
-		config:       conf,
+		ctx:            baseContext(),
+		mode:           mode,
+		aofMode:        aofMode,
+		nodeFailTime:   make(map[string]time.Time),
+		splitTime:      make(map[string]time.Time),
+		state:          stateInit,
+		logger:         logger,
+		loggerCloser:   loggerCloser,
+		logLevel:       logLevelWriter,
+		reloadRequests: make(chan chan ConfigReloadResult),
+		configHolder:   config.NewHolder(conf),
+		localConfig:    &localConf,
+		configFile:     configFile,
 	}
 	app.critical.Store(false)
 	return app, nil
 }
 
+// config returns current effective config snapshot, it is safe to use from any goroutine
+// but should never be modified: changes are published by applyEffectiveConfig only
+func (app *App) config() *config.Config {
+	return app.configHolder.Get()
+}
+
 func (app *App) connectDCS() error {
 	var err error
-	app.dcs, err = dcs.NewZookeeper(app.ctx, &app.config.Zookeeper, app.logger)
+	app.dcs, err = dcs.NewZookeeper(app.ctx, &app.config().Zookeeper, app.logger)
 	if err != nil {
 		return fmt.Errorf("failed to connect to zkDCS: %s", err.Error())
 	}
@@ -120,22 +148,27 @@ func (app *App) CloseLogger() {
 }
 
 func (app *App) lockDaemonFile() {
-	app.daemonLock = flock.New(app.config.DaemonLockFile)
+	app.daemonLock = flock.New(app.config().DaemonLockFile)
 	if locked, err := app.daemonLock.TryLock(); !locked {
 		msg := "another instance is running."
 		if err != nil {
 			msg = err.Error()
 		}
-		app.logger.Error().Str("error", msg).Msgf("Unable to acquire daemon lock on %s", app.config.DaemonLockFile)
+		app.logger.Error().Str("error", msg).Msgf("Unable to acquire daemon lock on %s", app.config().DaemonLockFile)
 		app.CloseLogger()
 		os.Exit(1)
 	}
+	// pid is used by cli to signal running daemon (e.g. config reload)
+	err := os.WriteFile(app.config().DaemonLockFile, []byte(strconv.Itoa(os.Getpid())), 0o644)
+	if err != nil {
+		app.logger.Warn().Err(err).Msgf("Unable to write pid to daemon lock file %s", app.config().DaemonLockFile)
+	}
 }
 
 func (app *App) unlockDaemonFile() {
 	err := app.daemonLock.Unlock()
 	if err != nil {
-		app.logger.Error().Err(err).Msgf("Unable to unlock daemon lock %s", app.config.DaemonLockFile)
+		app.logger.Error().Err(err).Msgf("Unable to unlock daemon lock %s", app.config().DaemonLockFile)
 	}
 }
 
@@ -145,7 +178,7 @@ func (app *App) Run() int {
 	defer app.unlockDaemonFile()
 	defer app.loggerCloser.Close()
 
-	app.timings = newTimingReporter(app.config, app.logger)
+	app.timings = newTimingReporter(app.config(), app.logger)
 	defer app.timings.Close()
 
 	err := app.connectDCS()
@@ -156,10 +189,10 @@ func (app *App) Run() int {
 	defer app.dcs.Close()
 	app.dcs.SetDisconnectCallback(func() error { return app.handleCritical() })
 
-	app.shard = valkey.NewShard(app.config, app.logger, app.dcs)
+	app.shard = valkey.NewShard(app.configHolder, app.logger, app.dcs)
 	defer app.shard.Close()
-	if app.mode == modeSentinel {
-		app.cache, err = valkey.NewSentiCacheNode(app.config, app.logger)
+	if app.mode == modeSentinel && !app.config().Witness {
+		app.cache, err = valkey.NewSentiCacheNode(app.configHolder, app.logger)
 		if err != nil {
 			app.logger.Error().Err(err).Msg("Unable to init senticache node")
 			return 1
@@ -169,18 +202,29 @@ func (app *App) Run() int {
 	}
 
 	go app.pprofHandler()
-	go app.healthChecker()
+	if !app.config().Witness {
+		go app.healthChecker()
+	}
+	go app.observer()
 	go app.stateFileHandler()
 
 	sighup := make(chan os.Signal, 1)
 	signal.Notify(sighup, syscall.SIGHUP)
 
-	ticker := time.NewTicker(app.config.TickInterval)
+	ticker := time.NewTicker(app.config().TickInterval)
 	for {
 		select {
 		case <-sighup:
 			app.timings.Reopen()
+			app.reloadConfig()
+			ticker.Reset(app.config().TickInterval)
+		case resultChan := <-app.reloadRequests:
+			resultChan <- app.reloadConfig()
+			ticker.Reset(app.config().TickInterval)
 		case <-ticker.C:
+			if app.dcs.IsConnected() {
+				app.refreshShardConfig()
+			}
 			for {
 				app.logger.Info().Msgf("Rdsync state: %s", app.state)
 				stateHandler := map[appState](func() appState){
@@ -189,6 +233,7 @@ func (app *App) Run() int {
 					stateCandidate:   app.stateCandidate,
 					stateLost:        app.stateLost,
 					stateMaintenance: app.stateMaintenance,
+					stateWitness:     app.stateWitness,
 				}[app.state]
 				if stateHandler == nil {
 					panic(fmt.Sprintf("Unknown state: %s", app.state))
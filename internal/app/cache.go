@@ -15,16 +15,16 @@ func (app *App) updateCache(refState map[string]*HostState, cache *valkey.SentiC
 			continue
 		}
 
-		if hostState.SentiCacheState != nil && fqdn != app.config.Hostname {
+		if hostState.SentiCacheState != nil && fqdn != app.config().Hostname {
 			var sentinel valkey.SentiCacheSentinel
 			sentinel.Name = hostState.SentiCacheState.Name
 			sentinel.RunID = hostState.SentiCacheState.RunID
-			if app.config.SentinelMode.AnnounceHostname {
+			if app.config().SentinelMode.AnnounceHostname {
 				sentinel.IP = fqdn
 			} else {
 				sentinel.IP = hostState.IP
 			}
-			sentinel.Port = app.config.SentinelMode.CachePort
+			sentinel.Port = app.config().SentinelMode.CachePort
 			state.Sentinels = append(state.Sentinels, sentinel)
 		}
 
@@ -40,17 +40,17 @@ func (app *App) updateCache(refState map[string]*HostState, cache *valkey.SentiC
 				continue
 			}
 			masterReadOnly = hostState.IsReadOnly
-			state.Master.Name = app.config.SentinelMode.ClusterName
+			state.Master.Name = app.config().SentinelMode.ClusterName
 			state.Master.IP = hostState.IP
-			if app.config.SentinelMode.AnnounceHostname {
+			if app.config().SentinelMode.AnnounceHostname {
 				state.Master.IP = fqdn
 			} else {
 				state.Master.IP = hostState.IP
 			}
-			state.Master.Port = app.config.Valkey.Port
+			state.Master.Port = app.config().Valkey.Port
 			state.Master.RunID = hostState.RunID
 			state.Master.Quorum = len(refState)/2 + 1
-			state.Master.ParallelSyncs = app.config.Valkey.MaxParallelSyncs
+			state.Master.ParallelSyncs = app.config().Valkey.MaxParallelSyncs
 			state.Master.ConfigEpoch = 0
 		} else {
 			nc, err := app.shard.GetNodeConfiguration(fqdn)
@@ -58,18 +58,21 @@ func (app *App) updateCache(refState map[string]*HostState, cache *valkey.SentiC
 				return err
 			}
 			var replica valkey.SentiCacheReplica
-			if app.config.SentinelMode.AnnounceHostname {
+			if app.config().SentinelMode.AnnounceHostname {
 				replica.IP = fqdn
 			} else {
 				replica.IP = hostState.IP
 			}
-			replica.Port = app.config.Valkey.Port
+			replica.Port = app.config().Valkey.Port
 			replica.RunID = hostState.RunID
 			replica.MasterLinkDownTime = hostState.ReplicaState.MasterLinkDownTime
 			replica.SlavePriority = nc.Priority
+			if nc.NeverPromote {
+				replica.SlavePriority = 0
+			}
 			replica.ReplicaAnnounced = 1
 			replica.MasterHost = hostState.ReplicaState.MasterHost
-			replica.MasterPort = app.config.Valkey.Port
+			replica.MasterPort = app.config().Valkey.Port
 			if hostState.ReplicaState.MasterLinkState {
 				replica.SlaveMasterLinkStatus = 0
 			} else {
@@ -86,7 +89,7 @@ func (app *App) updateCache(refState map[string]*HostState, cache *valkey.SentiC
 }
 
 func (app *App) cacheUpdater() {
-	ticker := time.NewTicker(app.config.TickInterval)
+	ticker := time.NewTicker(app.config().TickInterval)
 	for {
 		select {
 		case <-ticker.C:
@@ -15,11 +15,17 @@ func (app *App) stateCandidate() appState {
 		app.logger.Error().Err(err).Msg("Candidate: failed to update host info from DCS")
 		return stateCandidate
 	}
+	err = app.refreshNodeConfigurations()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Candidate: failed to get hosts configuration from DCS")
+		return stateCandidate
+	}
 	shardState, err := app.getShardStateFromDB()
 	if err != nil {
 		app.logger.Error().Err(err).Msg("Failed to get shard state from DB")
 	} else {
 		app.logger.Info().Msgf("Shard state: %v", shardState)
+		app.reportSplitBrain(shardState)
 	}
 	maintenance, err := app.GetMaintenance()
 	if err != nil && !errors.Is(err, dcs.ErrNotFound) {
@@ -41,17 +47,36 @@ func (app *App) stateCandidate() appState {
 			app.logger.Error().Err(err).Msg("Candidate: failed to apply poison pill")
 			return stateCandidate
 		}
-		if poisonPill.TargetHost == app.config.Hostname {
+		if poisonPill.TargetHost == app.config().Hostname {
 			return stateCandidate
 		}
 	}
 
+	fenced, err := app.applyLocalFence()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Candidate: failed to apply fence")
+		return stateCandidate
+	}
+	if fenced {
+		app.logger.Info().Msg("Candidate: local host is fenced")
+		return stateCandidate
+	}
+
 	var master string
 	err = app.dcs.Get(pathMasterNode, &master)
 	if err != nil && !errors.Is(err, dcs.ErrNotFound) {
 		app.logger.Error().Err(err).Msg("Candidate: failed to get current master from DCS")
 		return stateCandidate
 	}
+	inMaintenance, err := app.isLocalHostInMaintenance()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Candidate: failed to get local host maintenance from DCS")
+		return stateCandidate
+	}
+	if inMaintenance {
+		app.logger.Info().Msg("Candidate: local host is under maintenance")
+		return stateCandidate
+	}
 	app.repairLocalNode(master)
 
 	if app.dcs.AcquireLock(pathManagerLock) {
@@ -3,17 +3,21 @@ package app
 import (
 	"bufio"
 	"context"
+	json "encoding/json/v2"
 	"errors"
 	"fmt"
 
 	"os"
 	"slices"
 	"sort"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"gopkg.in/yaml.v2"
 
+	"github.com/yandex/rdsync/internal/config"
 	"github.com/yandex/rdsync/internal/dcs"
 	"github.com/yandex/rdsync/internal/valkey"
 )
@@ -28,7 +32,7 @@ func (app *App) CliInfo(verbose bool) int {
 	app.dcs.Initialize()
 	defer app.dcs.Close()
 
-	app.shard = valkey.NewShard(app.config, app.logger, app.dcs)
+	app.shard = valkey.NewShard(app.configHolder, app.logger, app.dcs)
 	defer app.shard.Close()
 	if err := app.shard.UpdateHostsInfo(); err != nil {
 		app.logger.Error().Err(err).Msg("Unable to update hosts info")
@@ -46,6 +50,22 @@ func (app *App) CliInfo(verbose bool) int {
 		}
 		data[pathHANodes] = haNodes
 
+		hostMaintenance := make(map[string]string)
+		for _, host := range haNodes {
+			nc, err := app.shard.GetNodeConfiguration(host)
+			if err != nil {
+				app.logger.Error().Err(err).Msgf("Failed to get configuration of %s", host)
+				return 1
+			}
+			if nc.Maintenance != nil {
+				hostMaintenance[host] = fmt.Sprintf("<by %s at %s: %s>", nc.Maintenance.InitiatedBy,
+					nc.Maintenance.InitiatedAt, nc.Maintenance.Reason)
+			}
+		}
+		if len(hostMaintenance) > 0 {
+			data["host_maintenance"] = hostMaintenance
+		}
+
 		activeNodes, err := app.GetActiveNodes()
 		if err != nil {
 			app.logger.Error().Err(err).Msg("Failed to get active nodes")
@@ -65,7 +85,19 @@ func (app *App) CliInfo(verbose bool) int {
 		}
 		data[pathHealthPrefix] = health
 
-		for _, path := range []string{pathLastSwitch, pathCurrentSwitch, pathLastRejectedSwitch} {
+		if err := app.refreshNodeConfigurations(); err != nil {
+			app.logger.Error().Err(err).Msg("Failed to get hosts configuration")
+			return 1
+		}
+		zones := make(map[string]string)
+		for _, zone := range app.zoneHealth(shardState) {
+			zones[zone.name] = fmt.Sprintf("%d/%d alive", zone.alive, zone.total)
+		}
+		if len(zones) > 0 {
+			data["zones"] = zones
+		}
+
+		for _, path := range []string{pathLastSwitch, pathCurrentSwitch, pathLastRejectedSwitch, pathLastFailedSwitch} {
 			var switchover Switchover
 			err = app.dcs.Get(path, &switchover)
 			if err == nil {
@@ -94,6 +126,59 @@ func (app *App) CliInfo(verbose bool) int {
 			return 1
 		}
 
+		fences, err := app.getFences()
+		if err != nil {
+			app.logger.Error().Err(err).Msgf("Failed to get %s", pathFencesPrefix)
+			return 1
+		}
+		if len(fences) > 0 {
+			fenceInfo := make(map[string]string, len(fences))
+			for host, fence := range fences {
+				fenceInfo[host] = fence.String()
+			}
+			data[pathFencesPrefix] = fenceInfo
+		}
+
+		splitBrain, err := app.getSplitBrain()
+		if err != nil {
+			app.logger.Error().Err(err).Msgf("Failed to get %s", pathSplitBrain)
+			return 1
+		}
+		if splitBrain != nil {
+			data[pathSplitBrain] = splitBrain.String()
+		}
+
+		scheduled, err := app.getScheduledOperations()
+		if err != nil {
+			app.logger.Error().Err(err).Msgf("Failed to get %s", pathScheduledPrefix)
+			return 1
+		}
+		if len(scheduled) > 0 {
+			scheduledInfo := make(map[string]string, len(scheduled))
+			for _, op := range scheduled {
+				scheduledInfo[op.ID] = op.String()
+			}
+			data[pathScheduledPrefix] = scheduledInfo
+		}
+
+		freeze, err := app.getFailoverFreeze()
+		if err != nil {
+			app.logger.Error().Err(err).Msgf("Failed to get %s", pathFailoverFreeze)
+			return 1
+		}
+		if freeze != nil {
+			data[pathFailoverFreeze] = freeze.String()
+		}
+
+		shardConfig, err := app.getShardConfig()
+		if err != nil {
+			app.logger.Error().Err(err).Msgf("Failed to get %s", pathShardConfig)
+			return 1
+		}
+		if len(shardConfig) > 0 {
+			data[pathShardConfig] = shardConfig
+		}
+
 		var manager dcs.LockOwner
 		err = app.dcs.Get(pathManagerLock, &manager)
 		if err != nil && !errors.Is(err, dcs.ErrNotFound) {
@@ -109,6 +194,40 @@ func (app *App) CliInfo(verbose bool) int {
 			return 1
 		}
 		data[pathMasterNode] = master
+
+		app.refreshShardConfig()
+		quorum := map[string]any{"policy": app.config().Valkey.ReplicationQuorumPolicy}
+		if app.config().Valkey.ReplicationQuorumPolicy == quorumPolicyFixed {
+			quorum["size"] = app.config().Valkey.ReplicationQuorumSize
+		}
+		if master != "" {
+			quorum["replicas_to_write"] = app.getNumReplicasToWrite(activeNodes, master)
+			quorum["failover_quorum"] = app.getFailoverQuorum(activeNodes, master)
+		}
+		data["replication_quorum"] = quorum
+
+		if app.config().Valkey.FailoverBudget > 0 || app.config().Valkey.FailoverBackoff {
+			budget, err := app.getFailoverBudget()
+			if err != nil {
+				app.logger.Error().Err(err).Msgf("Failed to get %s", pathFailoverBudget)
+				return 1
+			}
+			data[pathFailoverBudget] = app.failoverBudgetStatus(budget)
+		}
+
+		observations, err := app.getObservations()
+		if err != nil {
+			app.logger.Error().Err(err).Msgf("Failed to get %s", pathObservationsPrefix)
+			return 1
+		}
+		if master != "" {
+			down, total := app.countObservers(observations, master)
+			data["master_observers"] = fmt.Sprintf("%d/%d see master down, %d required", down, total,
+				app.config().Valkey.FailoverObserverQuorum)
+		}
+		if witnesses := app.getWitnesses(observations); len(witnesses) > 0 {
+			data["witnesses"] = witnesses
+		}
 		tree = data
 	} else {
 		tree, err = app.dcs.GetTree("")
@@ -135,7 +254,7 @@ func (app *App) CliState(verbose bool) int {
 	}
 	defer app.dcs.Close()
 	app.dcs.Initialize()
-	app.shard = valkey.NewShard(app.config, app.logger, app.dcs)
+	app.shard = valkey.NewShard(app.configHolder, app.logger, app.dcs)
 	defer app.shard.Close()
 
 	if err := app.shard.UpdateHostsInfo(); err != nil {
@@ -177,8 +296,8 @@ func matchPrefix(hosts []string, prefix string) []string {
 	return matched
 }
 
-// CliSwitch performs manual switch-over of the master node
-func (app *App) CliSwitch(switchFrom, switchTo string, waitTimeout time.Duration, switchForce bool) int {
+// CliSwitch performs manual switch-over of the master node (or prints its plan only)
+func (app *App) CliSwitch(switchFrom, switchTo string, waitTimeout time.Duration, switchForce, plan bool, ignoredChecks []string) int {
 	if switchFrom == "" && switchTo == "" {
 		app.logger.Error().Msg("Either --from or --to should be set")
 		return 1
@@ -198,7 +317,8 @@ func (app *App) CliSwitch(switchFrom, switchTo string, waitTimeout time.Duration
 	}
 	defer app.dcs.Close()
 	app.dcs.Initialize()
-	app.shard = valkey.NewShard(app.config, app.logger, app.dcs)
+	app.refreshShardConfig()
+	app.shard = valkey.NewShard(app.configHolder, app.logger, app.dcs)
 	defer app.shard.Close()
 
 	if err := app.shard.UpdateHostsInfo(); err != nil {
@@ -206,6 +326,11 @@ func (app *App) CliSwitch(switchFrom, switchTo string, waitTimeout time.Duration
 		return 1
 	}
 
+	if err := app.refreshNodeConfigurations(); err != nil {
+		app.logger.Error().Err(err).Msg("Unable to get hosts configuration")
+		return 1
+	}
+
 	if len(app.shard.Hosts()) == 1 {
 		app.logger.Info().Msg("switchover makes no sense on single node shard")
 		fmt.Println("switchover done")
@@ -245,6 +370,10 @@ func (app *App) CliSwitch(switchFrom, switchTo string, waitTimeout time.Duration
 			app.logger.Error().Msgf("%s is not active, can't switch to it", toHost)
 			return 1
 		}
+		if reason := app.promotionBlocker(toHost); reason != "" {
+			app.logger.Error().Msgf("%s is %s, can't switch to it", toHost, reason)
+			return 1
+		}
 	} else {
 		notDesired := matchPrefix(app.shard.Hosts(), switchFrom)
 		if len(notDesired) == 0 {
@@ -274,7 +403,7 @@ func (app *App) CliSwitch(switchFrom, switchTo string, waitTimeout time.Duration
 				app.logger.Error().Err(err).Msg("No actual shard state")
 				return 1
 			}
-			toHost, err = app.getMostDesirableNode(states, switchFrom)
+			toHost, _, err = app.getMostDesirableNode(states, switchFrom, app.hostZone(currentMaster))
 			if err != nil {
 				app.logger.Error().Err(err).Msg("No desirable node")
 				return 1
@@ -282,6 +411,21 @@ func (app *App) CliSwitch(switchFrom, switchTo string, waitTimeout time.Duration
 		}
 	}
 
+	if plan {
+		return app.printSwitchoverPlan(currentMaster, fromHost, toHost, activeNodes, ignoredChecks)
+	}
+
+	states, err := app.getShardStateFromDB()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("No actual shard state")
+		return 1
+	}
+	checked := &Switchover{From: fromHost, To: toHost, IgnoredChecks: ignoredChecks}
+	if err := app.checkSwitchoverPreconditions(checked, activeNodes, states, currentMaster); err != nil {
+		app.logger.Error().Err(err).Msg("Use --ignore-<check> to override")
+		return 1
+	}
+
 	var switchover Switchover
 	err = app.dcs.Get(pathCurrentSwitch, &switchover)
 	if err == nil {
@@ -295,9 +439,10 @@ func (app *App) CliSwitch(switchFrom, switchTo string, waitTimeout time.Duration
 
 	switchover.From = fromHost
 	switchover.To = toHost
-	switchover.InitiatedBy = app.config.Hostname
+	switchover.InitiatedBy = app.config().Hostname
 	switchover.InitiatedAt = time.Now()
 	switchover.Cause = CauseManual
+	switchover.IgnoredChecks = ignoredChecks
 	if switchForce {
 		switchover.RunCount = 1
 		err = app.dcs.Set(pathActiveNodes, []string{toHost})
@@ -318,29 +463,9 @@ func (app *App) CliSwitch(switchFrom, switchTo string, waitTimeout time.Duration
 	}
 	// wait for switchover to complete
 	if waitTimeout > 0 {
-		var lastSwitchover Switchover
-		waitCtx, cancel := context.WithTimeout(app.ctx, waitTimeout)
-		defer cancel()
-		ticker := time.NewTicker(time.Second)
-	Out:
-		for {
-			select {
-			case <-ticker.C:
-				lastSwitchover = app.getLastSwitchover()
-				if lastSwitchover.InitiatedBy == switchover.InitiatedBy && lastSwitchover.InitiatedAt.Unix() == switchover.InitiatedAt.Unix() {
-					break Out
-				} else {
-					lastSwitchover = Switchover{}
-				}
-			case <-waitCtx.Done():
-				break Out
-			}
-		}
-		if lastSwitchover.Result == nil {
-			app.logger.Error().Msg("Switchover did not finish until deadline")
-			return 1
-		} else if !lastSwitchover.Result.Ok {
-			app.logger.Error().Msg("Could not wait for switchover to complete because of errors")
+		err = app.waitSwitchover(&switchover, waitTimeout)
+		if err != nil {
+			app.logger.Error().Err(err).Msg("Could not wait for switchover to complete")
 			return 1
 		}
 		fmt.Println("switchover done")
@@ -351,7 +476,7 @@ func (app *App) CliSwitch(switchFrom, switchTo string, waitTimeout time.Duration
 }
 
 // CliEnableMaintenance enables maintenance mode
-func (app *App) CliEnableMaintenance(waitTimeout time.Duration) int {
+func (app *App) CliEnableMaintenance(waitTimeout time.Duration, reason string, ttl time.Duration) int {
 	err := app.connectDCS()
 	if err != nil {
 		app.logger.Error().Err(err).Msg("Unable to connect to dcs")
@@ -361,11 +486,34 @@ func (app *App) CliEnableMaintenance(waitTimeout time.Duration) int {
 	app.dcs.Initialize()
 
 	maintenance := &Maintenance{
-		InitiatedBy: app.config.Hostname,
+		InitiatedBy: app.config().Hostname,
 		InitiatedAt: time.Now(),
+		Reason:      reason,
+	}
+	if ttl > 0 {
+		maintenance.ExpiresAt = maintenance.InitiatedAt.Add(ttl)
 	}
 	err = app.dcs.Create(pathMaintenance, maintenance)
-	if err != nil && !errors.Is(err, dcs.ErrExists) {
+	if errors.Is(err, dcs.ErrExists) {
+		// maintenance is already enabled: keep its state, but apply new reason and ttl
+		err = dcs.Update(app.dcs, pathMaintenance, func(existing *Maintenance) error {
+			if existing.InitiatedAt.IsZero() || existing.ShouldLeave {
+				return fmt.Errorf("maintenance is being disabled now, retry later")
+			}
+			if reason != "" {
+				existing.Reason = reason
+			}
+			if ttl > 0 {
+				existing.ExpiresAt = maintenance.InitiatedAt.Add(ttl)
+			}
+			return nil
+		})
+		if err != nil {
+			app.logger.Error().Err(err).Msg("Unable to update maintenance in dcs")
+			return 1
+		}
+		app.logger.Info().Msg("Maintenance is already enabled, reason and ttl updated")
+	} else if err != nil {
 		app.logger.Error().Err(err).Msg("Unable to create maintenance path in dcs")
 		return 1
 	}
@@ -484,6 +632,128 @@ func (app *App) CliGetMaintenance() int {
 	}
 }
 
+// CliGetFailoverFreeze prints current failover freeze status
+func (app *App) CliGetFailoverFreeze() int {
+	err := app.connectDCS()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Unable to connect to dcs")
+		return 1
+	}
+	defer app.dcs.Close()
+	app.dcs.Initialize()
+
+	freeze, err := app.getFailoverFreeze()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Unable to get failover freeze status")
+		return 1
+	}
+	switch {
+	case freeze == nil:
+		fmt.Println("off")
+	case freeze.Expired():
+		fmt.Printf("expired %s\n", freeze)
+	default:
+		fmt.Printf("on %s\n", freeze)
+	}
+	return 0
+}
+
+// CliGetFailoverBudget prints automatic failover budget usage
+func (app *App) CliGetFailoverBudget() int {
+	err := app.connectDCS()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Unable to connect to dcs")
+		return 1
+	}
+	defer app.dcs.Close()
+	app.dcs.Initialize()
+	app.refreshShardConfig()
+
+	budget, err := app.getFailoverBudget()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Unable to get failover budget")
+		return 1
+	}
+	fmt.Println(app.failoverBudgetStatus(budget))
+	return 0
+}
+
+// CliResetFailoverBudget forgets automatic failovers made before now
+func (app *App) CliResetFailoverBudget(reason string) int {
+	if reason == "" {
+		app.logger.Error().Msg("Failover budget reset reason is required")
+		return 1
+	}
+	err := app.connectDCS()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Unable to connect to dcs")
+		return 1
+	}
+	defer app.dcs.Close()
+	app.dcs.Initialize()
+	app.refreshShardConfig()
+
+	// versioned write keeps failover recorded by manager concurrently
+	budget, err := app.resetFailoverBudget(reason)
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Unable to reset failover budget")
+		return 1
+	}
+	fmt.Printf("failover budget reset: %s\n", app.failoverBudgetStatus(budget))
+	return 0
+}
+
+// CliEnableFailoverFreeze forbids automatic failover (and optionally manual switchover)
+func (app *App) CliEnableFailoverFreeze(reason string, duration time.Duration, freezeSwitchover bool) int {
+	if reason == "" {
+		app.logger.Error().Msg("Failover freeze reason is required")
+		return 1
+	}
+	err := app.connectDCS()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Unable to connect to dcs")
+		return 1
+	}
+	defer app.dcs.Close()
+	app.dcs.Initialize()
+
+	freeze := &FailoverFreeze{
+		InitiatedAt:      time.Now(),
+		InitiatedBy:      app.config().Hostname,
+		Reason:           reason,
+		FreezeSwitchover: freezeSwitchover,
+	}
+	if duration > 0 {
+		freeze.ExpiresAt = freeze.InitiatedAt.Add(duration)
+	}
+	err = app.dcs.Set(pathFailoverFreeze, freeze)
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Unable to set failover freeze")
+		return 1
+	}
+	fmt.Printf("failover frozen %s\n", freeze)
+	return 0
+}
+
+// CliDisableFailoverFreeze removes failover freeze
+func (app *App) CliDisableFailoverFreeze() int {
+	err := app.connectDCS()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Unable to connect to dcs")
+		return 1
+	}
+	defer app.dcs.Close()
+	app.dcs.Initialize()
+
+	err = app.dcs.Delete(pathFailoverFreeze)
+	if err != nil && !errors.Is(err, dcs.ErrNotFound) {
+		app.logger.Error().Err(err).Msg("Unable to remove failover freeze")
+		return 1
+	}
+	fmt.Println("failover unfrozen")
+	return 0
+}
+
 // CliAbort cleans switchover node from DCS
 func (app *App) CliAbort() int {
 	err := app.connectDCS()
@@ -537,7 +807,7 @@ func (app *App) CliHostList() int {
 	app.dcs.Initialize()
 	defer app.dcs.Close()
 
-	app.shard = valkey.NewShard(app.config, app.logger, app.dcs)
+	app.shard = valkey.NewShard(app.configHolder, app.logger, app.dcs)
 	defer app.shard.Close()
 
 	data := make(map[string]any)
@@ -560,7 +830,21 @@ func (app *App) CliHostList() int {
 }
 
 // CliHostAdd add hosts to the list of hosts in dcs
-func (app *App) CliHostAdd(host string, priority *int, dryRun bool, skipValkeyCheck bool) int {
+// checkHostAlive checks that valkey on host is reachable before adding it to shard
+func (app *App) checkHostAlive(host string) error {
+	node, err := valkey.NewNode(app.configHolder, app.logger, host)
+	if err != nil {
+		return fmt.Errorf("failed to check connection to %s, can't tell if it's alive: %w", host, err)
+	}
+	defer node.Close()
+	_, _, _, _, _, err = node.GetState(app.ctx)
+	if err != nil {
+		return fmt.Errorf("node %s is dead: %w", host, err)
+	}
+	return nil
+}
+
+func (app *App) CliHostAdd(host string, priority *int, dryRun bool, skipValkeyCheck bool, join bool, joinWait time.Duration) int {
 	if priority != nil && *priority < 0 {
 		app.logger.Error().Msgf("Priority must be >= 0. Got: %d", *priority)
 		return 1
@@ -574,7 +858,7 @@ func (app *App) CliHostAdd(host string, priority *int, dryRun bool, skipValkeyCh
 	defer app.dcs.Close()
 	app.dcs.Initialize()
 
-	app.shard = valkey.NewShard(app.config, app.logger, app.dcs)
+	app.shard = valkey.NewShard(app.configHolder, app.logger, app.dcs)
 	defer app.shard.Close()
 
 	// root path probably does not exist
@@ -584,21 +868,41 @@ func (app *App) CliHostAdd(host string, priority *int, dryRun bool, skipValkeyCh
 	}
 
 	if !skipValkeyCheck {
-		node, err := valkey.NewNode(app.config, app.logger, host)
-		if err != nil {
-			app.logger.Error().Err(err).Msgf("Failed to check connection to %s, can't tell if it's alive", host)
+		if err := app.checkHostAlive(host); err != nil {
+			app.logger.Error().Err(err).Msg("Unable to add host")
 			return 1
 		}
-		defer node.Close()
-		_, _, _, _, _, err = node.GetState(app.ctx)
+	}
+
+	if join && !dryRun {
+		hosts, err := app.shard.GetShardHostsFromDcs()
 		if err != nil {
-			app.logger.Error().Err(err).Msgf("Node %s is dead", host)
+			app.logger.Error().Err(err).Msg("Failed to get hosts")
 			return 1
 		}
+		if slices.Contains(hosts, host) {
+			nc, err := app.shard.GetNodeConfiguration(host)
+			if err != nil {
+				app.logger.Error().Err(err).Msgf("Unable to get configuration of %s", host)
+				return 1
+			}
+			if !nc.Joining {
+				app.logger.Error().Msgf("%s is already a shard member", host)
+				return 1
+			}
+		} else {
+			joining := valkey.DefaultNodeConfiguration()
+			joining.Joining = true
+			err = app.dcs.Create(dcs.JoinPath(pathHANodes, host), joining)
+			if err != nil && !errors.Is(err, dcs.ErrExists) {
+				app.logger.Error().Err(err).Msgf("Unable to create dcs path for %s", host)
+				return 1
+			}
+		}
 	}
 
 	if !dryRun && priority == nil {
-		err = app.dcs.Set(dcs.JoinPath(pathHANodes, host), *valkey.DefaultNodeConfiguration())
+		err = app.dcs.Create(dcs.JoinPath(pathHANodes, host), *valkey.DefaultNodeConfiguration())
 		if err != nil && !errors.Is(err, dcs.ErrExists) {
 			app.logger.Error().Err(err).Msgf("Unable to create dcs path for %s", host)
 			return 1
@@ -619,11 +923,14 @@ func (app *App) CliHostAdd(host string, priority *int, dryRun bool, skipValkeyCh
 	}
 
 	fmt.Println("host has been added")
+	if join {
+		return app.waitJoin(host, joinWait)
+	}
 	return 0
 }
 
 // CliHostRemove removes host from the list of hosts in dcs
-func (app *App) CliHostRemove(host string) int {
+func (app *App) CliHostRemove(host string, force, dryRun bool) int {
 	err := app.connectDCS()
 	if err != nil {
 		app.logger.Error().Err(err).Msg("Unable to connect to dcs")
@@ -631,16 +938,114 @@ func (app *App) CliHostRemove(host string) int {
 	}
 	defer app.dcs.Close()
 	app.dcs.Initialize()
+	app.refreshShardConfig()
 
-	err = app.dcs.Delete(dcs.JoinPath(pathHANodes, host))
-	if err != nil && !errors.Is(err, dcs.ErrNotFound) {
-		app.logger.Error().Err(err).Msgf("Unable to delete dcs path for %s", host)
+	app.shard = valkey.NewShard(app.configHolder, app.logger, app.dcs)
+	defer app.shard.Close()
+	if err := app.shard.UpdateHostsInfo(); err != nil {
+		app.logger.Error().Err(err).Msg("Unable to update hosts info")
+		return 1
+	}
+	if err := app.refreshNodeConfigurations(); err != nil {
+		app.logger.Error().Err(err).Msg("Unable to get hosts configuration")
+		return 1
+	}
+	if !slices.Contains(app.shard.Hosts(), host) {
+		if dryRun {
+			fmt.Println("dry run finished: no changes detected")
+		} else {
+			fmt.Println("host has been removed")
+		}
+		return 0
+	}
+
+	prohibited, err := app.removalBlockers([]string{host})
+	if err != nil {
+		app.logger.Error().Err(err).Msgf("Unable to check removal of %s", host)
+		return 1
+	}
+	for _, reason := range prohibited {
+		if force {
+			app.logger.Warn().Msgf("Removing anyway: %s", reason)
+		} else {
+			app.logger.Error().Msgf("Unable to remove %s: %s (use --force to override)", host, reason)
+		}
+	}
+	if len(prohibited) > 0 && !force {
+		return 1
+	}
+	if dryRun {
+		fmt.Printf("dry run: %s can be removed\n", host)
+		return 2
+	}
+
+	if err := app.removeHost(host); err != nil {
+		app.logger.Error().Err(err).Msgf("Unable to remove %s", host)
 		return 1
 	}
 	fmt.Println("host has been removed")
 	return 0
 }
 
+// removalBlockers prints quorum changes caused by removal of hosts and returns reasons making it unsafe
+func (app *App) removalBlockers(hosts []string) ([]string, error) {
+	var master string
+	err := app.dcs.Get(pathMasterNode, &master)
+	if err != nil && !errors.Is(err, dcs.ErrNotFound) {
+		return nil, fmt.Errorf("get current master: %w", err)
+	}
+	activeNodes, err := app.GetActiveNodes()
+	if err != nil {
+		return nil, fmt.Errorf("get active nodes: %w", err)
+	}
+	remaining := filterOut(activeNodes, hosts)
+	fmt.Printf("replicas to write: %d -> %d, failover quorum: %d -> %d\n",
+		app.getNumReplicasToWrite(activeNodes, master), app.getNumReplicasToWrite(remaining, master),
+		app.getFailoverQuorum(activeNodes, master), app.getFailoverQuorum(remaining, master))
+
+	var prohibited []string
+	var shardState map[string]*HostState
+	for _, host := range hosts {
+		if host == master {
+			prohibited = append(prohibited, fmt.Sprintf("%s is current master, switch it over first", host))
+			continue
+		}
+		if !slices.Contains(activeNodes, host) {
+			continue
+		}
+		if shardState == nil {
+			shardState, err = app.getShardStateFromDB()
+			if err != nil {
+				return nil, fmt.Errorf("get shard state: %w", err)
+			}
+		}
+		// hosts are checked one by one as removal of each next one starts from already reduced shard
+		if err := app.checkDrainQuorum(host, master, activeNodes, shardState); err != nil {
+			prohibited = append(prohibited, err.Error())
+		}
+		activeNodes = filterOut(activeNodes, []string{host})
+	}
+	return prohibited, nil
+}
+
+// removeHost deletes host configuration with its health and observation nodes from dcs.
+// Manager drops removed host from active nodes and quorum replicas under its lock.
+func (app *App) removeHost(host string) error {
+	err := app.dcs.Delete(dcs.JoinPath(pathHANodes, host))
+	if err != nil && !errors.Is(err, dcs.ErrNotFound) {
+		return err
+	}
+	err = app.dcs.Delete(dcs.JoinPath(pathHealthPrefix, host))
+	if err != nil && !errors.Is(err, dcs.ErrNotFound) {
+		app.logger.Warn().Err(err).Msgf("Unable to delete health of %s", host)
+	}
+	err = app.dcs.Delete(dcs.JoinPath(pathObservationsPrefix, host))
+	if err != nil && !errors.Is(err, dcs.ErrNotFound) {
+		app.logger.Warn().Err(err).Msgf("Unable to delete observation of %s", host)
+	}
+	return nil
+}
+
 func (app *App) processPriority(priority *int, dryRun bool, host string) (changes bool, err error) {
 	targetConf := valkey.DefaultNodeConfiguration()
 	if priority != nil {
@@ -668,10 +1073,191 @@ func (app *App) processPriority(priority *int, dryRun bool, host string) (change
 		return true, nil
 	}
 
-	err = app.dcs.Set(dcs.JoinPath(pathHANodes, host), targetConf)
+	// keep other per-host settings
+	currentConf, err := app.shard.GetNodeConfiguration(host)
+	if err != nil {
+		return false, err
+	}
+	currentConf.Priority = targetConf.Priority
+	err = app.dcs.Set(dcs.JoinPath(pathHANodes, host), currentConf)
 	if err != nil && !errors.Is(err, dcs.ErrExists) {
 		return false, err
 	}
 
 	return true, nil
 }
+
+// CliConfigDump prints effective config with secrets redacted
+func (app *App) CliConfigDump() int {
+	data, err := yaml.Marshal(app.config().Redacted())
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Failed to marshal yaml")
+		return 1
+	}
+	fmt.Print(string(data))
+	return 0
+}
+
+// CliConfigReload asks running rdsync daemon to reload config and prints the result
+func (app *App) CliConfigReload(waitTimeout time.Duration) int {
+	data, err := os.ReadFile(app.config().DaemonLockFile)
+	if err != nil {
+		app.logger.Error().Err(err).Msgf("Unable to read daemon pid from %s", app.config().DaemonLockFile)
+		return 1
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		app.logger.Error().Err(err).Msgf("Malformed daemon pid in %s", app.config().DaemonLockFile)
+		return 1
+	}
+	requestedAt := time.Now()
+	err = syscall.Kill(pid, syscall.SIGHUP)
+	if err != nil {
+		app.logger.Error().Err(err).Msgf("Unable to send SIGHUP to rdsync daemon (pid %d)", pid)
+		return 1
+	}
+	if waitTimeout <= 0 {
+		fmt.Println("config reload requested")
+		return 0
+	}
+	var result ConfigReloadResult
+	waitCtx, cancel := context.WithTimeout(app.ctx, waitTimeout)
+	defer cancel()
+	ticker := time.NewTicker(time.Second)
+Out:
+	for {
+		select {
+		case <-ticker.C:
+			data, err = os.ReadFile(app.config().ReloadResultFile)
+			if err != nil {
+				continue
+			}
+			if err = json.Unmarshal(data, &result); err != nil {
+				app.logger.Error().Err(err).Msgf("Malformed config reload result in %s", app.config().ReloadResultFile)
+				return 1
+			}
+			if result.ReloadedAt.After(requestedAt) {
+				break Out
+			}
+		case <-waitCtx.Done():
+			app.logger.Error().Msg("Rdsync did not report config reload within timeout")
+			return 1
+		}
+	}
+	out, err := yaml.Marshal(result)
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Failed to marshal yaml")
+		return 1
+	}
+	fmt.Print(string(out))
+	if result.Error != "" {
+		return 1
+	}
+	return 0
+}
+
+// CliConfigGet prints config value (or all shard-wide values if key is empty).
+// With shard flag only overrides stored in dcs are printed.
+func (app *App) CliConfigGet(key string, shard bool) int {
+	err := app.connectDCS()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Unable to connect to dcs")
+		return 1
+	}
+	app.dcs.Initialize()
+	defer app.dcs.Close()
+
+	overrides, err := app.getShardConfig()
+	if err != nil {
+		app.logger.Error().Err(err).Msgf("Failed to get %s", pathShardConfig)
+		return 1
+	}
+	var tree any
+	if shard {
+		if key == "" {
+			tree = overrides
+		} else {
+			value, ok := overrides[key]
+			if !ok {
+				app.logger.Error().Msgf("%s is not set shard-wide", key)
+				return 1
+			}
+			tree = map[string]string{key: value}
+		}
+	} else {
+		effective, err := app.config().WithShardOverrides(overrides)
+		if err != nil {
+			app.logger.Warn().Err(err).Msg("Some shard config overrides were skipped")
+		}
+		if key == "" {
+			tree, err = effective.ShardSettings()
+			if err != nil {
+				app.logger.Error().Err(err).Msg("Failed to get config values")
+				return 1
+			}
+		} else {
+			value, err := effective.GetValue(key)
+			if err != nil {
+				app.logger.Error().Err(err).Msg("Failed to get config value")
+				return 1
+			}
+			tree = map[string]string{key: value}
+		}
+	}
+	data, err := yaml.Marshal(tree)
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Failed to marshal yaml")
+		return 1
+	}
+	fmt.Print(string(data))
+	return 0
+}
+
+// CliConfigSet stores shard-wide config override in dcs
+func (app *App) CliConfigSet(key, value string) int {
+	if err := config.ValidateShardOverride(key, value); err != nil {
+		app.logger.Error().Err(err).Msg("Invalid shard config value")
+		return 1
+	}
+	parsed := *app.localConfig
+	_ = parsed.SetValue(key, value)
+	if err := validateConfig(&parsed); err != nil {
+		app.logger.Error().Err(err).Msg("Invalid shard config value")
+		return 1
+	}
+	return app.updateShardConfig(func(overrides map[string]string) {
+		overrides[key] = value
+	})
+}
+
+// CliConfigUnset removes shard-wide config override from dcs
+func (app *App) CliConfigUnset(key string) int {
+	return app.updateShardConfig(func(overrides map[string]string) {
+		delete(overrides, key)
+	})
+}
+
+func (app *App) updateShardConfig(update func(map[string]string)) int {
+	err := app.connectDCS()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Unable to connect to dcs")
+		return 1
+	}
+	app.dcs.Initialize()
+	defer app.dcs.Close()
+
+	// versioned write keeps concurrent config set/unset calls from losing each other's updates
+	err = dcs.Update(app.dcs, pathShardConfig, func(overrides *map[string]string) error {
+		if *overrides == nil {
+			*overrides = make(map[string]string)
+		}
+		update(*overrides)
+		return nil
+	})
+	if err != nil {
+		app.logger.Error().Err(err).Msgf("Failed to update %s", pathShardConfig)
+		return 1
+	}
+	fmt.Println("shard config updated, hosts apply it on next tick")
+	return 0
+}
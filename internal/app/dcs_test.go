@@ -0,0 +1,146 @@
+package app
+
+import (
+	json "encoding/json/v2"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yandex/rdsync/internal/dcs"
+)
+
+// testDCS is in-memory dcs for unit tests
+type testDCS struct {
+	nodes    map[string][]byte
+	versions map[string]int32
+	// beforeSetVersioned is called once before next versioned write to simulate concurrent update
+	beforeSetVersioned func()
+	mu                 sync.Mutex
+	// writes counts successful modifications
+	writes int
+}
+
+func newTestDCS() *testDCS {
+	return &testDCS{nodes: make(map[string][]byte), versions: make(map[string]int32)}
+}
+
+func (d *testDCS) IsConnected() bool                        { return true }
+func (d *testDCS) WaitConnected(timeout time.Duration) bool { return true }
+func (d *testDCS) Initialize()                              {}
+func (d *testDCS) SetDisconnectCallback(func() error)       {}
+func (d *testDCS) AcquireLock(path string) bool             { return true }
+func (d *testDCS) ReleaseLock(path string)                  {}
+func (d *testDCS) Close()                                   {}
+
+func (d *testDCS) Create(path string, value any) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.nodes[path]; ok {
+		return dcs.ErrExists
+	}
+	return d.set(path, value)
+}
+
+func (d *testDCS) CreateEphemeral(path string, value any) error {
+	return d.Create(path, value)
+}
+
+func (d *testDCS) set(path string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	d.nodes[path] = data
+	d.versions[path]++
+	d.writes++
+	return nil
+}
+
+func (d *testDCS) Set(path string, value any) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.set(path, value)
+}
+
+func (d *testDCS) SetEphemeral(path string, value any) error {
+	return d.Set(path, value)
+}
+
+func (d *testDCS) Get(path string, dest any) error {
+	_, err := d.GetVersioned(path, dest)
+	return err
+}
+
+func (d *testDCS) GetVersioned(path string, dest any) (int32, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	data, ok := d.nodes[path]
+	if !ok {
+		return dcs.VersionMissing, dcs.ErrNotFound
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		return dcs.VersionMissing, dcs.ErrMalformed
+	}
+	return d.versions[path], nil
+}
+
+func (d *testDCS) SetVersioned(path string, value any, version int32) error {
+	d.mu.Lock()
+	hook := d.beforeSetVersioned
+	d.beforeSetVersioned = nil
+	d.mu.Unlock()
+	if hook != nil {
+		hook()
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	current, ok := d.versions[path]
+	if !ok {
+		current = dcs.VersionMissing
+	}
+	if _, exists := d.nodes[path]; !exists {
+		current = dcs.VersionMissing
+	}
+	if current != version {
+		return dcs.ErrVersionMismatch
+	}
+	return d.set(path, value)
+}
+
+func (d *testDCS) Delete(path string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for node := range d.nodes {
+		if node == path || strings.HasPrefix(node, path+"/") {
+			delete(d.nodes, node)
+			d.writes++
+		}
+	}
+	return nil
+}
+
+func (d *testDCS) GetTree(path string) (any, error) {
+	return nil, nil
+}
+
+func (d *testDCS) GetChildren(path string) ([]string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	seen := make(map[string]bool)
+	var children []string
+	for node := range d.nodes {
+		rest, ok := strings.CutPrefix(node, path+"/")
+		if !ok {
+			continue
+		}
+		child, _, _ := strings.Cut(rest, "/")
+		if !seen[child] {
+			seen[child] = true
+			children = append(children, child)
+		}
+	}
+	if len(children) == 0 {
+		return nil, dcs.ErrNotFound
+	}
+	return children, nil
+}
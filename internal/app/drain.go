@@ -0,0 +1,166 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/yandex/rdsync/internal/dcs"
+	"github.com/yandex/rdsync/internal/valkey"
+)
+
+// checkDrainQuorum verifies that shard keeps failover quorum without drained host
+func (app *App) checkDrainQuorum(host, master string, activeNodes []string, shardState map[string]*HostState) error {
+	remaining := filterOut(activeNodes, []string{host})
+	if len(remaining) == 0 {
+		return fmt.Errorf("%s is the only active node", host)
+	}
+	replicas := countAliveHAReplicasWithinNodes(app.votingNodes(remaining), shardState)
+	if host == master {
+		// one of remaining replicas becomes master
+		replicas--
+	}
+	failoverQuorum := app.getFailoverQuorum(remaining, master)
+	if replicas < failoverQuorum {
+		return fmt.Errorf("shard loses quorum without %s: %d alive replicas left while %d is required",
+			host, replicas, failoverQuorum)
+	}
+	return nil
+}
+
+// CliHostDrain takes host out of service: makes it non-promotable, switches master away from it
+// and waits for it to leave active nodes
+func (app *App) CliHostDrain(host string, waitTimeout time.Duration) int {
+	err := app.connectDCS()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Unable to connect to dcs")
+		return 1
+	}
+	defer app.dcs.Close()
+	app.dcs.Initialize()
+	app.shard = valkey.NewShard(app.configHolder, app.logger, app.dcs)
+	defer app.shard.Close()
+
+	if err := app.shard.UpdateHostsInfo(); err != nil {
+		app.logger.Error().Err(err).Msg("Unable to update hosts info")
+		return 1
+	}
+	if err := app.refreshNodeConfigurations(); err != nil {
+		app.logger.Error().Err(err).Msg("Unable to get hosts configuration")
+		return 1
+	}
+	if !slices.Contains(app.shard.Hosts(), host) {
+		app.logger.Error().Msgf("Host %s is not in shard", host)
+		return 1
+	}
+	var master string
+	if err := app.dcs.Get(pathMasterNode, &master); err != nil {
+		app.logger.Error().Err(err).Msg("Failed to get current master")
+		return 1
+	}
+	activeNodes, err := app.GetActiveNodes()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Unable to get active nodes")
+		return 1
+	}
+	shardState, err := app.getShardStateFromDB()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("No actual shard state")
+		return 1
+	}
+	if slices.Contains(activeNodes, host) {
+		if err := app.checkDrainQuorum(host, master, activeNodes, shardState); err != nil {
+			app.logger.Error().Err(err).Msgf("Unable to drain %s", host)
+			return 1
+		}
+	}
+
+	err = app.updateNodeConfiguration(host, func(nc *valkey.NodeConfiguration) {
+		nc.Drained = true
+	})
+	if err != nil {
+		app.logger.Error().Err(err).Msgf("Unable to mark %s as drained", host)
+		return 1
+	}
+	fmt.Printf("%s marked as drained\n", host)
+
+	var switchover Switchover
+	if host == master {
+		// drained master stays active until switchover moves master away from it
+		switchover = Switchover{
+			From:        host,
+			InitiatedBy: app.config().Hostname,
+			InitiatedAt: time.Now(),
+			Cause:       CauseManual,
+		}
+		err = app.dcs.Create(pathCurrentSwitch, switchover)
+		if errors.Is(err, dcs.ErrExists) {
+			app.logger.Error().Msg("Another switchover in progress, retry drain after it finishes")
+			return 2
+		}
+		if err != nil {
+			app.logger.Error().Err(err).Msg("Unable to create switchover in dcs")
+			return 1
+		}
+		fmt.Printf("switchover from %s scheduled\n", host)
+	}
+	if waitTimeout <= 0 {
+		fmt.Println("drain scheduled")
+		return 0
+	}
+
+	deadline := time.Now().Add(waitTimeout)
+	if host == master {
+		err = app.waitSwitchover(&switchover, time.Until(deadline))
+		if err != nil {
+			app.logger.Error().Err(err).Msg("Could not wait for switchover to complete")
+			return 1
+		}
+		fmt.Println("switchover done")
+	}
+
+	waitCtx, cancel := context.WithDeadline(app.ctx, deadline)
+	defer cancel()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		activeNodes, err = app.GetActiveNodes()
+		if err != nil {
+			app.logger.Error().Err(err).Msg("Unable to get active nodes")
+		} else if !slices.Contains(activeNodes, host) {
+			fmt.Printf("%s is drained, safe to power off\n", host)
+			return 0
+		}
+		select {
+		case <-ticker.C:
+		case <-waitCtx.Done():
+			app.logger.Error().Msgf("%s did not leave active nodes within timeout", host)
+			return 1
+		}
+	}
+}
+
+// CliHostUndrain returns drained host to service
+func (app *App) CliHostUndrain(host string) int {
+	err := app.connectDCS()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Unable to connect to dcs")
+		return 1
+	}
+	defer app.dcs.Close()
+	app.dcs.Initialize()
+	app.shard = valkey.NewShard(app.configHolder, app.logger, app.dcs)
+	defer app.shard.Close()
+
+	err = app.updateNodeConfiguration(host, func(nc *valkey.NodeConfiguration) {
+		nc.Drained = false
+	})
+	if err != nil {
+		app.logger.Error().Err(err).Msgf("Unable to undrain %s", host)
+		return 1
+	}
+	fmt.Printf("%s undrained, it returns to active nodes once replication is healthy\n", host)
+	return 0
+}
@@ -0,0 +1,24 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex/rdsync/internal/config"
+)
+
+func TestCheckDrainQuorum(t *testing.T) {
+	conf, err := config.DefaultConfig()
+	require.NoError(t, err)
+	app := &App{configHolder: config.NewHolder(&conf)}
+	replica := &HostState{PingOk: true, PingStable: true, ReplicaState: &ReplicaState{}}
+	master := &HostState{PingOk: true, PingStable: true}
+	shardState := map[string]*HostState{"m": master, "r1": replica, "r2": replica}
+	activeNodes := []string{"m", "r1", "r2"}
+
+	require.NoError(t, app.checkDrainQuorum("r2", "m", activeNodes, shardState))
+	require.NoError(t, app.checkDrainQuorum("m", "m", activeNodes, shardState))
+	require.Error(t, app.checkDrainQuorum("r1", "m", []string{"m", "r1"}, shardState))
+	require.Error(t, app.checkDrainQuorum("m", "m", []string{"m"}, shardState))
+}
@@ -3,6 +3,7 @@ package app
 import (
 	"io"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
@@ -67,6 +68,19 @@ func (r *TimingReporter) reportTiming(eventType string, duration time.Duration)
 	r.logger.Info().Str("event", eventType).Int64("duration_ms", duration.Milliseconds()).Msg("event_timing")
 }
 
+// reportEvent logs an instant event with affected hosts to the timing log file.
+// If the reporter is nil (not configured), this is a no-op.
+func (r *TimingReporter) reportEvent(eventType string, hosts []string) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.logger.Info().Str("event", eventType).Str("hosts", strings.Join(hosts, ",")).Msg("event")
+}
+
 // Reopen closes the current log file and opens it again at the same path.
 // This supports log rotation: an external tool renames the file, then sends
 // SIGHUP, and the reporter starts writing to a new file at the original path.
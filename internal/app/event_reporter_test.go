@@ -71,6 +71,36 @@ func TestReportTimingWritesToFile(t *testing.T) {
 	require.Contains(t, output, "msg=event_timing")
 }
 
+func TestReportEventWritesToFile(t *testing.T) {
+	tmpFile, err := os.CreateTemp("", "rdsync_event_test_*.log")
+	require.NoError(t, err)
+	tmpPath := tmpFile.Name()
+	tmpFile.Close()
+	defer os.Remove(tmpPath)
+
+	conf := &config.Config{
+		EventTimingLogFile: tmpPath,
+		LogBufferSize:      1000,
+		LogPollInterval:    10 * time.Millisecond,
+	}
+	logger := testLogger()
+
+	reporter := newTimingReporter(conf, logger)
+	require.NotNil(t, reporter)
+
+	reporter.reportEvent("split_brain_detected", []string{"valkey1", "valkey2"})
+	reporter.Close()
+
+	content, err := os.ReadFile(tmpPath)
+	require.NoError(t, err)
+
+	output := string(content)
+	require.Contains(t, output, "event=split_brain_detected")
+	require.Contains(t, output, "hosts=valkey1,valkey2")
+	require.Contains(t, output, "msg=event")
+	require.NotContains(t, output, "duration_ms")
+}
+
 func TestNewTimingReporterInvalidPath(t *testing.T) {
 	conf := &config.Config{
 		EventTimingLogFile: "/nonexistent/directory/timing.log",
@@ -19,9 +19,9 @@ func countRunningHAReplicas(shardState map[string]*HostState) int {
 	return cnt
 }
 
-func (app *App) getFailoverQuorum(activeNodes []string) int {
-	fq := len(activeNodes) - app.getNumReplicasToWrite(activeNodes)
-	if fq < 1 || app.config.Valkey.AllowDataLoss {
+func (app *App) getFailoverQuorum(activeNodes []string, master string) int {
+	fq := len(app.votingNodes(activeNodes)) - app.getNumReplicasToWrite(activeNodes, master)
+	if fq < 1 || app.config().Valkey.AllowDataLoss {
 		fq = 1
 	}
 	return fq
@@ -30,18 +30,31 @@ func (app *App) getFailoverQuorum(activeNodes []string) int {
 func (app *App) performFailover(master string) error {
 	var switchover Switchover
 	switchover.From = master
-	switchover.InitiatedBy = app.config.Hostname
+	switchover.InitiatedBy = app.config().Hostname
 	switchover.InitiatedAt = time.Now()
 	switchover.Cause = CauseAuto
-	return app.dcs.Create(pathCurrentSwitch, switchover)
+	err := app.dcs.Create(pathCurrentSwitch, switchover)
+	if err != nil {
+		return err
+	}
+	if err = app.recordFailover(switchover.InitiatedAt); err != nil {
+		app.logger.Error().Err(err).Msg("Failed to record failover in budget")
+	}
+	return nil
 }
 
 func (app *App) approveFailover(shardState map[string]*HostState, activeNodes []string, master string) error {
-	if app.config.Valkey.FailoverTimeout > 0 {
+	if err := app.checkFailoverFreeze(CauseAuto); err != nil {
+		return err
+	}
+	if err := app.checkFailoverBudget(); err != nil {
+		return err
+	}
+	if app.config().Valkey.FailoverTimeout > 0 {
 		failedTime := time.Since(app.nodeFailTime[master])
-		if failedTime < app.config.Valkey.FailoverTimeout {
+		if failedTime < app.config().Valkey.FailoverTimeout {
 			return fmt.Errorf("failover timeout is not yet elapsed: remaining %v",
-				app.config.Valkey.FailoverTimeout-failedTime)
+				app.config().Valkey.FailoverTimeout-failedTime)
 		}
 	}
 	if countRunningHAReplicas(shardState) == len(shardState)-1 {
@@ -49,11 +62,28 @@ func (app *App) approveFailover(shardState map[string]*HostState, activeNodes []
 	}
 
 	app.logger.Info().Msgf("Approve failover: active nodes are %v", activeNodes)
-	permissibleReplicas := countAliveHAReplicasWithinNodes(activeNodes, shardState)
-	failoverQuorum := app.getFailoverQuorum(activeNodes)
+	permissibleReplicas := countAliveHAReplicasWithinNodes(app.votingNodes(activeNodes), shardState)
+	failoverQuorum := app.getFailoverQuorum(activeNodes, master)
 	if permissibleReplicas < failoverQuorum {
 		return fmt.Errorf("no quorum, have %d replicas while %d is required", permissibleReplicas, failoverQuorum)
 	}
+	if app.config().Valkey.FailoverObserverQuorum > 0 {
+		observations, err := app.getObservations()
+		if err != nil {
+			return err
+		}
+		down, total := app.countObservers(observations, master)
+		if down < app.config().Valkey.FailoverObserverQuorum {
+			return fmt.Errorf("no observer quorum, %d of %d observers see master down while %d is required",
+				down, total, app.config().Valkey.FailoverObserverQuorum)
+		}
+	}
+	if app.config().Valkey.FailoverZoneMajority {
+		aliveZones, totalZones := app.countZones(app.votingNodes(activeNodes), shardState)
+		if totalZones > 0 && aliveZones < totalZones/2+1 {
+			return fmt.Errorf("no zone majority, replicas are alive in %d of %d zones", aliveZones, totalZones)
+		}
+	}
 
 	var lastSwitchover Switchover
 	err := app.dcs.Get(pathLastSwitch, &lastSwitchover)
@@ -65,10 +95,18 @@ func (app *App) approveFailover(shardState map[string]*HostState, activeNodes []
 			return fmt.Errorf("another switchover with cause %s is in progress", lastSwitchover.Cause)
 		}
 		timeAfterLastSwitchover := time.Since(lastSwitchover.Result.FinishedAt)
-		if timeAfterLastSwitchover < app.config.Valkey.FailoverCooldown && lastSwitchover.Cause == CauseAuto {
+		if timeAfterLastSwitchover < app.config().Valkey.FailoverCooldown && lastSwitchover.Cause == CauseAuto {
 			return fmt.Errorf("not enough time from last failover %s (cooldown %s)",
-				lastSwitchover.Result.FinishedAt, app.config.Valkey.FailoverCooldown)
+				lastSwitchover.Result.FinishedAt, app.config().Valkey.FailoverCooldown)
 		}
 	}
-	return nil
+
+	var candidates []string
+	for _, host := range activeNodes {
+		state, ok := shardState[host]
+		if host != master && ok && state.PingOk && app.promotionBlocker(host) == "" {
+			candidates = append(candidates, host)
+		}
+	}
+	return app.verifyFailover(master, candidates)
 }
@@ -0,0 +1,85 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yandex/rdsync/internal/dcs"
+)
+
+// getFailoverBudget returns automatic failover history (empty if there is no one)
+func (app *App) getFailoverBudget() (*FailoverBudget, error) {
+	var budget FailoverBudget
+	err := app.dcs.Get(pathFailoverBudget, &budget)
+	if err != nil && !errors.Is(err, dcs.ErrNotFound) {
+		return nil, err
+	}
+	return &budget, nil
+}
+
+// checkFailoverBudget returns error if automatic failover budget is exhausted or backoff is not yet elapsed
+func (app *App) checkFailoverBudget() error {
+	if app.config().Valkey.FailoverBudget <= 0 && !app.config().Valkey.FailoverBackoff {
+		return nil
+	}
+	budget, err := app.getFailoverBudget()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	next := budget.nextAllowed(now, app.config().Valkey.FailoverBudget, app.config().Valkey.FailoverBudgetWindow,
+		app.config().Valkey.FailoverCooldown, app.config().Valkey.FailoverBackoff)
+	if next.After(now) {
+		return fmt.Errorf("failover budget exhausted: %s, next failover allowed at %s",
+			app.failoverBudgetUsage(budget, now), next)
+	}
+	return nil
+}
+
+func (app *App) failoverBudgetUsage(budget *FailoverBudget, now time.Time) string {
+	used := len(budget.used(now, app.config().Valkey.FailoverBudgetWindow))
+	if app.config().Valkey.FailoverBudget <= 0 {
+		return fmt.Sprintf("%d used within %s", used, app.config().Valkey.FailoverBudgetWindow)
+	}
+	return fmt.Sprintf("%d/%d used within %s", used, app.config().Valkey.FailoverBudget, app.config().Valkey.FailoverBudgetWindow)
+}
+
+// failoverBudgetStatus returns human-readable budget usage and next allowed failover time
+func (app *App) failoverBudgetStatus(budget *FailoverBudget) string {
+	now := time.Now()
+	status := app.failoverBudgetUsage(budget, now)
+	next := budget.nextAllowed(now, app.config().Valkey.FailoverBudget, app.config().Valkey.FailoverBudgetWindow,
+		app.config().Valkey.FailoverCooldown, app.config().Valkey.FailoverBackoff)
+	if next.After(now) {
+		status += fmt.Sprintf(", next failover allowed at %s", next)
+	}
+	if !budget.ResetAt.IsZero() {
+		status += fmt.Sprintf(", reset by %s at %s: %s", budget.ResetBy, budget.ResetAt, budget.ResetReason)
+	}
+	return status
+}
+
+// recordFailover adds automatic failover to history dropping ones outside of window
+func (app *App) recordFailover(ts time.Time) error {
+	return dcs.Update(app.dcs, pathFailoverBudget, func(budget *FailoverBudget) error {
+		budget.Failovers = append(budget.used(ts, app.config().Valkey.FailoverBudgetWindow), ts)
+		return nil
+	})
+}
+
+// resetFailoverBudget marks budget as reset keeping failover history
+func (app *App) resetFailoverBudget(reason string) (*FailoverBudget, error) {
+	var reset FailoverBudget
+	err := dcs.Update(app.dcs, pathFailoverBudget, func(budget *FailoverBudget) error {
+		budget.ResetAt = time.Now()
+		budget.ResetBy = app.config().Hostname
+		budget.ResetReason = reason
+		reset = *budget
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &reset, nil
+}
@@ -0,0 +1,59 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex/rdsync/internal/config"
+)
+
+func TestFailoverBudgetNextAllowed(t *testing.T) {
+	now := time.Now()
+	budget := &FailoverBudget{Failovers: []time.Time{
+		now.Add(-25 * time.Hour),
+		now.Add(-5 * time.Hour),
+		now.Add(-1 * time.Hour),
+	}}
+	require.Len(t, budget.used(now, 24*time.Hour), 2)
+
+	require.True(t, budget.nextAllowed(now, 3, 24*time.Hour, 30*time.Minute, false).IsZero())
+	require.Equal(t, now.Add(19*time.Hour), budget.nextAllowed(now, 2, 24*time.Hour, 30*time.Minute, false))
+
+	// two recent failovers double cooldown
+	require.Equal(t, now.Add(-1*time.Hour).Add(time.Hour), budget.nextAllowed(now, 0, 24*time.Hour, 30*time.Minute, true))
+	require.Equal(t, now.Add(-1*time.Hour).Add(2*time.Hour), budget.nextAllowed(now, 0, 24*time.Hour, time.Hour, true))
+
+	budget.ResetAt = now.Add(-30 * time.Minute)
+	require.Empty(t, budget.used(now, 24*time.Hour))
+	require.True(t, budget.nextAllowed(now, 1, 24*time.Hour, time.Hour, true).IsZero())
+}
+
+func TestResetFailoverBudgetConcurrentFailover(t *testing.T) {
+	testDcs := newTestDCS()
+	conf, err := config.DefaultConfig()
+	require.NoError(t, err)
+	conf.Hostname = "valkey1"
+	app := &App{
+		logger:       testLogger(),
+		dcs:          testDcs,
+		configHolder: config.NewHolder(&conf),
+	}
+	first := time.Now().Add(-time.Minute)
+	require.NoError(t, app.recordFailover(first))
+
+	// manager records failover while reset is in flight
+	second := time.Now()
+	testDcs.beforeSetVersioned = func() {
+		require.NoError(t, app.recordFailover(second))
+	}
+	budget, err := app.resetFailoverBudget("tested")
+	require.NoError(t, err)
+	require.Equal(t, "tested", budget.ResetReason)
+
+	stored, err := app.getFailoverBudget()
+	require.NoError(t, err)
+	require.Len(t, stored.Failovers, 2)
+	require.Equal(t, "valkey1", stored.ResetBy)
+}
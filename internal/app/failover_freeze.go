@@ -0,0 +1,54 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/yandex/rdsync/internal/dcs"
+)
+
+// getFailoverFreeze returns current failover freeze or nil if there is no one
+func (app *App) getFailoverFreeze() (*FailoverFreeze, error) {
+	var freeze FailoverFreeze
+	err := app.dcs.Get(pathFailoverFreeze, &freeze)
+	if errors.Is(err, dcs.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &freeze, nil
+}
+
+// checkFailoverFreeze returns error if failover (or switchover with given cause) is frozen.
+// Expired freeze is treated as absent, it is removed by manager.
+func (app *App) checkFailoverFreeze(cause string) error {
+	freeze, err := app.getFailoverFreeze()
+	if err != nil {
+		return err
+	}
+	if freeze == nil || freeze.Expired() {
+		return nil
+	}
+	if cause == CauseAuto || freeze.FreezeSwitchover {
+		return fmt.Errorf("failover is frozen: %s", freeze)
+	}
+	return nil
+}
+
+// clearExpiredFailoverFreeze drops expired failover freeze
+func (app *App) clearExpiredFailoverFreeze() {
+	freeze, err := app.getFailoverFreeze()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Failed to get failover freeze from DCS")
+		return
+	}
+	if freeze == nil || !freeze.Expired() {
+		return
+	}
+	app.logger.Info().Msgf("Failover freeze %s expired, removing it", freeze)
+	err = app.dcs.Delete(pathFailoverFreeze)
+	if err != nil && !errors.Is(err, dcs.ErrNotFound) {
+		app.logger.Error().Err(err).Msg("Failed to remove expired failover freeze")
+	}
+}
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex/rdsync/internal/config"
+)
+
+func TestCheckFailoverFreeze(t *testing.T) {
+	app := &App{
+		logger:       testLogger(),
+		dcs:          newTestDCS(),
+		configHolder: config.NewHolder(&config.Config{Hostname: "valkey1"}),
+	}
+	require.NoError(t, app.checkFailoverFreeze(CauseAuto))
+
+	freeze := &FailoverFreeze{InitiatedAt: time.Now(), ExpiresAt: time.Now().Add(time.Hour)}
+	require.NoError(t, app.dcs.Set(pathFailoverFreeze, freeze))
+	require.Error(t, app.checkFailoverFreeze(CauseAuto))
+	require.NoError(t, app.checkFailoverFreeze(CauseManual))
+	require.NoError(t, app.checkFailoverFreeze(CauseWorker))
+
+	freeze.FreezeSwitchover = true
+	require.NoError(t, app.dcs.Set(pathFailoverFreeze, freeze))
+	require.Error(t, app.checkFailoverFreeze(CauseManual))
+	require.Error(t, app.checkFailoverFreeze(CauseWorker))
+
+	// active freeze is kept by manager
+	app.clearExpiredFailoverFreeze()
+	current, err := app.getFailoverFreeze()
+	require.NoError(t, err)
+	require.NotNil(t, current)
+
+	// expired freeze does not block anything and is not removed by check
+	freeze.ExpiresAt = time.Now().Add(-time.Minute)
+	require.NoError(t, app.dcs.Set(pathFailoverFreeze, freeze))
+	require.NoError(t, app.checkFailoverFreeze(CauseAuto))
+	require.NoError(t, app.checkFailoverFreeze(CauseManual))
+	current, err = app.getFailoverFreeze()
+	require.NoError(t, err)
+	require.NotNil(t, current)
+
+	app.clearExpiredFailoverFreeze()
+	current, err = app.getFailoverFreeze()
+	require.NoError(t, err)
+	require.Nil(t, current)
+
+	// freeze without expiry never expires
+	require.False(t, (&FailoverFreeze{}).Expired())
+}
@@ -0,0 +1,167 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/yandex/rdsync/internal/dcs"
+	"github.com/yandex/rdsync/internal/valkey"
+)
+
+// getFences returns manual poison pills by target host
+func (app *App) getFences() (map[string]*PoisonPill, error) {
+	hosts, err := app.dcs.GetChildren(pathFencesPrefix)
+	if errors.Is(err, dcs.ErrNotFound) {
+		return map[string]*PoisonPill{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	fences := make(map[string]*PoisonPill, len(hosts))
+	for _, host := range hosts {
+		fence, err := app.getFence(host)
+		if err != nil {
+			return nil, err
+		}
+		if fence != nil {
+			fences[host] = fence
+		}
+	}
+	return fences, nil
+}
+
+// getFence returns manual poison pill for host or nil if host is not fenced
+func (app *App) getFence(host string) (*PoisonPill, error) {
+	var fence PoisonPill
+	err := app.dcs.Get(dcs.JoinPath(pathFencesPrefix, host), &fence)
+	if errors.Is(err, dcs.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &fence, nil
+}
+
+// applyLocalFence makes local node offline if it is fenced. Returns true if local host is fenced.
+func (app *App) applyLocalFence() (bool, error) {
+	fence, err := app.getFence(app.config().Hostname)
+	if err != nil || fence == nil || fence.Expired() {
+		return false, err
+	}
+	err = app.setLocalOffline(fence)
+	if err != nil {
+		return true, err
+	}
+	if !fence.Applied {
+		fence.Applied = true
+		err = app.dcs.Set(dcs.JoinPath(pathFencesPrefix, app.config().Hostname), fence)
+	}
+	return true, err
+}
+
+// clearExpiredFences drops expired manual poison pills
+func (app *App) clearExpiredFences() {
+	fences, err := app.getFences()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Failed to get fences from DCS")
+		return
+	}
+	for host, fence := range fences {
+		if !fence.Expired() {
+			continue
+		}
+		app.logger.Info().Msgf("Fence %s expired, removing it", fence)
+		err = app.dcs.Delete(dcs.JoinPath(pathFencesPrefix, host))
+		if err != nil && !errors.Is(err, dcs.ErrNotFound) {
+			app.logger.Error().Err(err).Msgf("Failed to remove expired fence of %s", host)
+		}
+	}
+}
+
+// fenceBlocker checks that all hosts are shard members and current master is fenced with force only
+func fenceBlocker(hosts, haNodes []string, master string, force bool) error {
+	for _, host := range hosts {
+		if !slices.Contains(haNodes, host) {
+			return fmt.Errorf("%s is not a shard member", host)
+		}
+		if host == master && !force {
+			return fmt.Errorf("%s is current master, fencing it causes failover, use --force to proceed", host)
+		}
+	}
+	return nil
+}
+
+// CliFence makes hosts go offline until unfenced or fence expiry
+func (app *App) CliFence(hosts []string, reason string, ttl time.Duration, force bool) int {
+	if reason == "" {
+		app.logger.Error().Msg("Fence reason is required")
+		return 1
+	}
+	err := app.connectDCS()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Unable to connect to dcs")
+		return 1
+	}
+	defer app.dcs.Close()
+	app.dcs.Initialize()
+
+	app.shard = valkey.NewShard(app.configHolder, app.logger, app.dcs)
+	defer app.shard.Close()
+	haNodes, err := app.shard.GetShardHostsFromDcs()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Unable to get hosts from dcs")
+		return 1
+	}
+	var master string
+	err = app.dcs.Get(pathMasterNode, &master)
+	if err != nil && !errors.Is(err, dcs.ErrNotFound) {
+		app.logger.Error().Err(err).Msg("Unable to get current master from dcs")
+		return 1
+	}
+	if err := fenceBlocker(hosts, haNodes, master, force); err != nil {
+		app.logger.Error().Err(err).Msg("Unable to fence")
+		return 1
+	}
+	for _, host := range hosts {
+		fence := &PoisonPill{
+			InitiatedAt: time.Now(),
+			InitiatedBy: app.config().Hostname,
+			TargetHost:  host,
+			Cause:       reason,
+		}
+		if ttl > 0 {
+			fence.ExpiresAt = fence.InitiatedAt.Add(ttl)
+		}
+		err = app.dcs.Set(dcs.JoinPath(pathFencesPrefix, host), fence)
+		if err != nil {
+			app.logger.Error().Err(err).Msgf("Unable to fence %s", host)
+			return 1
+		}
+		fmt.Printf("fenced %s\n", fence)
+	}
+	return 0
+}
+
+// CliUnfence removes manual poison pills
+func (app *App) CliUnfence(hosts []string) int {
+	err := app.connectDCS()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Unable to connect to dcs")
+		return 1
+	}
+	defer app.dcs.Close()
+	app.dcs.Initialize()
+
+	for _, host := range hosts {
+		err = app.dcs.Delete(dcs.JoinPath(pathFencesPrefix, host))
+		if err != nil && !errors.Is(err, dcs.ErrNotFound) {
+			app.logger.Error().Err(err).Msgf("Unable to unfence %s", host)
+			return 1
+		}
+		fmt.Printf("unfenced %s\n", host)
+	}
+	return 0
+}
@@ -0,0 +1,52 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex/rdsync/internal/config"
+	"github.com/yandex/rdsync/internal/dcs"
+)
+
+func TestPoisonPillExpired(t *testing.T) {
+	require.False(t, (&PoisonPill{}).Expired())
+	require.False(t, (&PoisonPill{ExpiresAt: time.Now().Add(time.Minute)}).Expired())
+	require.True(t, (&PoisonPill{ExpiresAt: time.Now().Add(-time.Minute)}).Expired())
+}
+
+func TestFenceBlocker(t *testing.T) {
+	haNodes := []string{"m", "r1", "r2"}
+	require.NoError(t, fenceBlocker([]string{"r1", "r2"}, haNodes, "m", false))
+	require.Error(t, fenceBlocker([]string{"r1", "r3"}, haNodes, "m", true))
+	require.Error(t, fenceBlocker([]string{"r1", "m"}, haNodes, "m", false))
+	require.NoError(t, fenceBlocker([]string{"r1", "m"}, haNodes, "m", true))
+}
+
+func TestExpiredFences(t *testing.T) {
+	logger := zerolog.Nop()
+	app := &App{
+		ctx:          context.Background(),
+		logger:       &logger,
+		dcs:          newTestDCS(),
+		configHolder: config.NewHolder(&config.Config{Hostname: "r1"}),
+	}
+	expired := &PoisonPill{TargetHost: "r1", ExpiresAt: time.Now().Add(-time.Minute)}
+	active := &PoisonPill{TargetHost: "r2", ExpiresAt: time.Now().Add(time.Minute)}
+	require.NoError(t, app.dcs.Set(dcs.JoinPath(pathFencesPrefix, "r1"), expired))
+	require.NoError(t, app.dcs.Set(dcs.JoinPath(pathFencesPrefix, "r2"), active))
+
+	// expired fence is not applied to local host
+	fenced, err := app.applyLocalFence()
+	require.NoError(t, err)
+	require.False(t, fenced)
+
+	app.clearExpiredFences()
+	fences, err := app.getFences()
+	require.NoError(t, err)
+	require.Len(t, fences, 1)
+	require.Contains(t, fences, "r2")
+}
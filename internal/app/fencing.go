@@ -0,0 +1,69 @@
+package app
+
+import (
+	"fmt"
+)
+
+const (
+	// fencingBestEffort proceeds with promotion even if fencing failed
+	fencingBestEffort = "best_effort"
+	// fencingRequired aborts switchover if old master was not fenced
+	fencingRequired = "required"
+)
+
+func validateFencingPolicy(policy string) error {
+	switch policy {
+	case fencingBestEffort, fencingRequired:
+		return nil
+	}
+	return fmt.Errorf("unknown fencing policy: %s", policy)
+}
+
+// fenceHost runs fencing commands in order until one of them succeeds
+func (app *App) fenceHost(host, cause string) ([]CommandResult, error) {
+	env := []string{
+		"RDSYNC_FENCE_HOST=" + host,
+		"RDSYNC_CAUSE=" + cause,
+		"RDSYNC_MANAGER=" + app.config().Hostname,
+	}
+	var results []CommandResult
+	for _, command := range app.config().Valkey.FencingCommands {
+		result := app.runHookCommand(command, env, app.config().Valkey.FencingTimeout)
+		results = append(results, result)
+		if result.Error == "" {
+			app.logger.Info().Msgf("Fenced %s with %s: %s", host, command, result.Output)
+			return results, nil
+		}
+		app.logger.Error().Msgf("Fencing %s with %s failed: %s: %s", host, command, result.Error, result.Output)
+	}
+	return results, fmt.Errorf("all fencing commands failed for %s", host)
+}
+
+// ensureOldMasterFenced waits for poison pill on old master and fences it
+// with configured commands if poison pill was not applied
+func (app *App) ensureOldMasterFenced(switchover *Switchover, oldMaster string) error {
+	applied := false
+	if switchover.Cause != CauseAuto {
+		applied = app.waitPoisonPill(app.config().Valkey.WaitPoisonPillTimeout)
+	} else if poisonPill, err := app.getPoisonPill(); err == nil {
+		applied = poisonPill.TargetHost == oldMaster && poisonPill.Applied
+	}
+	if applied || len(app.config().Valkey.FencingCommands) == 0 {
+		return nil
+	}
+	if len(switchover.Fencing) > 0 && switchover.Fencing[len(switchover.Fencing)-1].Error == "" {
+		return nil
+	}
+	results, err := app.fenceHost(oldMaster, switchover.Cause)
+	switchover.Fencing = results
+	if updateErr := app.updateSwitchover(switchover); updateErr != nil {
+		app.logger.Error().Err(updateErr).Msg("Failed to record fencing results")
+	}
+	if err != nil {
+		if app.config().Valkey.FencingPolicy == fencingRequired {
+			return fmt.Errorf("old master %s is not fenced: %w", oldMaster, err)
+		}
+		app.logger.Error().Err(err).Msg("Old master is not fenced, proceeding as fencing policy is best effort")
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex/rdsync/internal/config"
+)
+
+func TestFenceHost(t *testing.T) {
+	logger := zerolog.Nop()
+	app := &App{
+		ctx:          context.Background(),
+		logger:       &logger,
+		configHolder: config.NewHolder(&config.Config{Valkey: config.ValkeyConfig{FencingTimeout: 10 * time.Second}}),
+	}
+	script := filepath.Join(t.TempDir(), "fence.sh")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\necho \"$RDSYNC_FENCE_HOST\"\nexit $1\n"), 0o755))
+
+	updateTestConfig(app, func(conf *config.Config) {
+		conf.Valkey.FencingCommands = []string{script + " 1", script + " 0", script + " 2"}
+	})
+	results, err := app.fenceHost("master", CauseAuto)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	require.Equal(t, 1, results[0].ExitCode)
+	require.Equal(t, "master", results[1].Output)
+
+	updateTestConfig(app, func(conf *config.Config) { conf.Valkey.FencingCommands = []string{script + " 1"} })
+	_, err = app.fenceHost("master", CauseAuto)
+	require.Error(t, err)
+
+	require.NoError(t, validateFencingPolicy(fencingRequired))
+	require.Error(t, validateFencingPolicy("always"))
+
+	conf, err := config.DefaultConfig()
+	require.NoError(t, err)
+	conf.Valkey.FencingPolicy = fencingRequired
+	require.Error(t, validateConfig(&conf))
+	conf.Valkey.FencingCommands = []string{script + " 0"}
+	require.NoError(t, validateConfig(&conf))
+}
@@ -0,0 +1,70 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yandex/rdsync/internal/dcs"
+	"github.com/yandex/rdsync/internal/valkey"
+)
+
+func (app *App) isHostInMaintenance(host string) bool {
+	nc, ok := app.nodeConfigs[host]
+	return ok && nc.Maintenance != nil
+}
+
+// isLocalHostInMaintenance checks dcs directly as local host state is needed before taking manager lock
+func (app *App) isLocalHostInMaintenance() (bool, error) {
+	nc, err := app.shard.GetNodeConfiguration(app.config().Hostname)
+	if err != nil {
+		return false, err
+	}
+	return nc.Maintenance != nil, nil
+}
+
+// CliHostMaintenance enables or disables maintenance of a single host
+func (app *App) CliHostMaintenance(host string, enable bool, reason string) int {
+	err := app.connectDCS()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Unable to connect to dcs")
+		return 1
+	}
+	defer app.dcs.Close()
+	app.dcs.Initialize()
+
+	app.shard = valkey.NewShard(app.configHolder, app.logger, app.dcs)
+	defer app.shard.Close()
+
+	var maintenance *valkey.HostMaintenance
+	if enable {
+		var master string
+		err = app.dcs.Get(pathMasterNode, &master)
+		if err != nil && !errors.Is(err, dcs.ErrNotFound) {
+			app.logger.Error().Err(err).Msg("Unable to get current master from dcs")
+			return 1
+		}
+		if host == master {
+			app.logger.Error().Msgf("%s is current master, switch it over before maintenance", host)
+			return 1
+		}
+		maintenance = &valkey.HostMaintenance{
+			InitiatedAt: time.Now(),
+			InitiatedBy: app.config().Hostname,
+			Reason:      reason,
+		}
+	}
+	err = app.updateNodeConfiguration(host, func(nc *valkey.NodeConfiguration) {
+		nc.Maintenance = maintenance
+	})
+	if err != nil {
+		app.logger.Error().Err(err).Msgf("Unable to update maintenance of %s", host)
+		return 1
+	}
+	if enable {
+		fmt.Printf("maintenance enabled on %s\n", host)
+	} else {
+		fmt.Printf("maintenance disabled on %s\n", host)
+	}
+	return 0
+}
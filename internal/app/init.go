@@ -1,13 +1,16 @@
 package app
 
 func (app *App) stateInit() appState {
-	if !app.dcs.WaitConnected(app.config.DcsWaitTimeout) {
+	if !app.dcs.WaitConnected(app.config().DcsWaitTimeout) {
 		if app.doesMaintenanceFileExist() {
 			return stateMaintenance
 		}
 		return stateInit
 	}
 	app.dcs.Initialize()
+	if app.config().Witness {
+		return stateWitness
+	}
 	if app.dcs.AcquireLock(pathManagerLock) {
 		return stateManager
 	}
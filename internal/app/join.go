@@ -0,0 +1,123 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yandex/rdsync/internal/valkey"
+)
+
+// joinProgress returns whether joining host is a healthy replica and human-readable progress
+func (app *App) joinProgress(host string, state, masterState *HostState, masterNode *valkey.Node) (bool, string) {
+	if state == nil || !state.PingOk {
+		return false, "host is not reachable"
+	}
+	rs := state.ReplicaState
+	if rs == nil || masterState == nil || !replicates(masterState, rs, host, masterNode, true) {
+		return false, "waiting for host to be attached as replica"
+	}
+	if rs.MasterSyncInProgress {
+		return false, "full sync in progress"
+	}
+	if !rs.MasterLinkState {
+		return false, "master link is down"
+	}
+	lag := masterState.MasterReplicationOffset - rs.ReplicationOffset
+	if lag > app.config().Valkey.JoinMaxLag {
+		return false, fmt.Sprintf("catching up: lag %d bytes (max %d)", lag, app.config().Valkey.JoinMaxLag)
+	}
+	return true, fmt.Sprintf("replica is healthy: lag %d bytes", lag)
+}
+
+// completeJoins makes joining hosts eligible for active nodes once they finish sync.
+// Progress is logged on join stage change only.
+func (app *App) completeJoins(shardState map[string]*HostState, master string) {
+	masterNode := app.shard.Get(master)
+	stages := make(map[string]string)
+	defer func() { app.joinStages = stages }()
+	for host, nc := range app.nodeConfigs {
+		if !nc.Joining || host == master {
+			continue
+		}
+		ready, progress := app.joinProgress(host, shardState[host], shardState[master], masterNode)
+		if !ready {
+			// stage is progress without changing details like lag
+			stage, _, _ := strings.Cut(progress, ":")
+			stages[host] = stage
+			if app.joinStages[host] != stage {
+				app.logger.Info().Msgf("Join of %s: %s", host, progress)
+			}
+			continue
+		}
+		err := app.updateNodeConfiguration(host, func(stored *valkey.NodeConfiguration) {
+			stored.Joining = false
+		})
+		if err != nil {
+			app.logger.Error().Err(err).Msgf("Failed to complete join of %s", host)
+			continue
+		}
+		nc.Joining = false
+		app.logger.Info().Msgf("Join of %s completed: %s", host, progress)
+	}
+}
+
+// waitJoin reports join progress until manager makes host eligible
+func (app *App) waitJoin(host string, waitTimeout time.Duration) int {
+	if waitTimeout <= 0 {
+		fmt.Println("join scheduled, manager will make host eligible once it is a healthy replica")
+		return 0
+	}
+	waitCtx, cancel := context.WithTimeout(app.ctx, waitTimeout)
+	defer cancel()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	var lastProgress string
+	for {
+		select {
+		case <-ticker.C:
+		case <-waitCtx.Done():
+			app.logger.Error().Msgf("%s did not join within timeout, manager keeps waiting for it", host)
+			return 1
+		}
+		progress, joined, err := app.getJoinStatus(host)
+		if err != nil {
+			app.logger.Error().Err(err).Msgf("Unable to get join status of %s", host)
+			continue
+		}
+		if joined {
+			fmt.Printf("%s has joined\n", host)
+			return 0
+		}
+		if progress != lastProgress {
+			fmt.Printf("%s: %s\n", host, progress)
+			lastProgress = progress
+		}
+	}
+}
+
+func (app *App) getJoinStatus(host string) (string, bool, error) {
+	err := app.shard.UpdateHostsInfo()
+	if err != nil {
+		return "", false, err
+	}
+	nc, err := app.shard.GetNodeConfiguration(host)
+	if err != nil {
+		return "", false, err
+	}
+	if !nc.Joining {
+		return "", true, nil
+	}
+	var master string
+	err = app.dcs.Get(pathMasterNode, &master)
+	if err != nil {
+		return "", false, err
+	}
+	shardState, err := app.getShardStateFromDB()
+	if err != nil {
+		return "", false, err
+	}
+	_, progress := app.joinProgress(host, shardState[host], shardState[master], app.shard.Get(master))
+	return progress, false, nil
+}
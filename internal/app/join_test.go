@@ -0,0 +1,65 @@
+package app
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex/rdsync/internal/config"
+	"github.com/yandex/rdsync/internal/valkey"
+)
+
+func TestJoinProgress(t *testing.T) {
+	conf, err := config.DefaultConfig()
+	require.NoError(t, err)
+	conf.Valkey.JoinMaxLag = 100
+	app := &App{configHolder: config.NewHolder(&conf)}
+	master := &HostState{PingOk: true, IsMaster: true, MasterReplicationOffset: 1000, ConnectedReplicas: []string{"r1"}}
+
+	ready, _ := app.joinProgress("r1", &HostState{PingOk: false}, master, nil)
+	require.False(t, ready)
+
+	ready, _ = app.joinProgress("r2", &HostState{PingOk: true, ReplicaState: &ReplicaState{MasterLinkState: true}}, master, nil)
+	require.False(t, ready)
+
+	ready, progress := app.joinProgress("r1", &HostState{PingOk: true, ReplicaState: &ReplicaState{MasterSyncInProgress: true}}, master, nil)
+	require.False(t, ready)
+	require.Equal(t, "full sync in progress", progress)
+
+	ready, _ = app.joinProgress("r1", &HostState{PingOk: true, ReplicaState: &ReplicaState{MasterLinkState: true, ReplicationOffset: 500}}, master, nil)
+	require.False(t, ready)
+
+	ready, _ = app.joinProgress("r1", &HostState{PingOk: true, ReplicaState: &ReplicaState{MasterLinkState: true, ReplicationOffset: 950}}, master, nil)
+	require.True(t, ready)
+}
+
+func TestCompleteJoinsLogsStageChanges(t *testing.T) {
+	var logs bytes.Buffer
+	logger := zerolog.New(&logs)
+	conf, err := config.DefaultConfig()
+	require.NoError(t, err)
+	conf.Valkey.JoinMaxLag = 100
+	app := &App{
+		logger:       &logger,
+		dcs:          newTestDCS(),
+		configHolder: config.NewHolder(&conf),
+		nodeConfigs:  map[string]*valkey.NodeConfiguration{"r1": {Joining: true}},
+	}
+	app.shard = valkey.NewShard(app.configHolder, app.logger, app.dcs)
+	master := &HostState{PingOk: true, IsMaster: true, MasterReplicationOffset: 1000, ConnectedReplicas: []string{"r1"}}
+	catchingUp := func(offset int64) map[string]*HostState {
+		return map[string]*HostState{"m": master, "r1": {PingOk: true,
+			ReplicaState: &ReplicaState{MasterLinkState: true, ReplicationOffset: offset}}}
+	}
+
+	app.completeJoins(catchingUp(100), "m")
+	app.completeJoins(catchingUp(200), "m")
+	require.Equal(t, 1, strings.Count(logs.String(), "Join of r1"))
+
+	app.completeJoins(map[string]*HostState{"m": master, "r1": {PingOk: false}}, "m")
+	app.completeJoins(map[string]*HostState{"m": master, "r1": {PingOk: false}}, "m")
+	require.Equal(t, 2, strings.Count(logs.String(), "Join of r1"))
+}
@@ -50,7 +50,9 @@ func (app *App) findMostRecentNode(shardState map[string]*HostState) string {
 	return recentHost
 }
 
-func (app *App) getMostDesirableNode(shardState map[string]*HostState, switchoverFrom string) (string, error) {
+// getMostDesirableNode selects new master within hosts with psync possible from most recent one
+// using configured candidate scoring
+func (app *App) getMostDesirableNode(shardState map[string]*HostState, switchoverFrom, preferredZone string) (string, []CandidateScore, error) {
 	recent := app.findMostRecentNode(shardState)
 	recentState := shardState[recent]
 
@@ -60,6 +62,9 @@ func (app *App) getMostDesirableNode(shardState map[string]*HostState, switchove
 		if strings.HasPrefix(host, switchoverFrom) {
 			continue
 		}
+		if app.promotionBlocker(host) != "" {
+			continue
+		}
 		if host == recent {
 			recentNodes = append(recentNodes, host)
 			continue
@@ -70,31 +75,23 @@ func (app *App) getMostDesirableNode(shardState map[string]*HostState, switchove
 	}
 
 	if len(recentNodes) < 1 {
-		return "", fmt.Errorf("no hosts with psync possible from most recent one: %s", recent)
+		return "", nil, fmt.Errorf("no hosts with psync possible from most recent one: %s", recent)
 	}
 
 	app.logger.Info().Msgf("Selecting most desirable within %s", recentNodes)
 
-	var priorityHost string
-	var maxPriority int
-	var maxOffset int64
-
+	candidates := make([]*failoverCandidate, 0, len(recentNodes))
 	for _, host := range recentNodes {
 		nc, err := app.shard.GetNodeConfiguration(host)
 		if err != nil {
-			return "", err
-		}
-		offset := getOffset(shardState[host])
-		if nc.Priority > maxPriority {
-			priorityHost = host
-			maxPriority = nc.Priority
-			maxOffset = offset
-		} else if nc.Priority == maxPriority && offset > maxOffset {
-			priorityHost = host
-			maxPriority = nc.Priority
-			maxOffset = offset
+			return "", nil, err
 		}
+		candidates = append(candidates, &failoverCandidate{
+			host:          host,
+			state:         shardState[host],
+			config:        nc,
+			preferredZone: preferredZone,
+		})
 	}
-
-	return priorityHost, nil
+	return app.scoreCandidates(candidates)
 }
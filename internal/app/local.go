@@ -15,8 +15,8 @@ func (app *App) getLocalState() *HostState {
 }
 
 func (app *App) healthChecker() {
-	ticker := time.NewTicker(app.config.HealthCheckInterval)
-	path := dcs.JoinPath(pathHealthPrefix, app.config.Hostname)
+	ticker := time.NewTicker(app.config().HealthCheckInterval)
+	path := dcs.JoinPath(pathHealthPrefix, app.config().Hostname)
 	hcCheckTime := time.Time{}
 	for {
 		select {
@@ -24,13 +24,18 @@ func (app *App) healthChecker() {
 			hc := app.getLocalState()
 			app.logger.Info().Msgf("healthcheck: %v", hc)
 			if hc != nil {
+				settings, err := app.config().ShardSettings()
+				if err != nil {
+					app.logger.Error().Err(err).Msg("Failed to get effective shard settings")
+				}
+				hc.EffectiveSettings = settings
 				hcCheckTime = hc.CheckAt
-				err := app.dcs.SetEphemeral(path, hc)
+				err = app.dcs.SetEphemeral(path, hc)
 				if err != nil {
 					app.logger.Error().Err(err).Msg("Failed to set healthcheck status to dcs")
 				}
 			} else if !hcCheckTime.IsZero() {
-				if time.Since(hcCheckTime) < 5*app.config.HealthCheckInterval {
+				if time.Since(hcCheckTime) < 5*app.config().HealthCheckInterval {
 					app.logger.Warn().Msg("Unable to get local node state, leaving health node in dcs intact")
 				} else {
 					app.logger.Warn().Msg("Unable to get local node state, dropping health node from dcs")
@@ -5,6 +5,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -32,7 +33,33 @@ func levelToUpper(i any) string {
 	return strings.ToUpper(fmt.Sprintf("%-5s", i))
 }
 
-func newMainLogger(level zerolog.Level, bufSize int, poll time.Duration) (*zerolog.Logger, io.Closer) {
+// levelFilterWriter drops events below current level.
+// Level is shared by all loggers derived from main one and could be changed at runtime.
+type levelFilterWriter struct {
+	out   io.Writer
+	level atomic.Int32
+}
+
+func (w *levelFilterWriter) Write(p []byte) (int, error) {
+	return w.out.Write(p)
+}
+
+func (w *levelFilterWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if level < w.getLevel() {
+		return len(p), nil
+	}
+	return w.out.Write(p)
+}
+
+func (w *levelFilterWriter) getLevel() zerolog.Level {
+	return zerolog.Level(w.level.Load())
+}
+
+func (w *levelFilterWriter) setLevel(level zerolog.Level) {
+	w.level.Store(int32(level))
+}
+
+func newMainLogger(level zerolog.Level, bufSize int, poll time.Duration) (*zerolog.Logger, io.Closer, *levelFilterWriter) {
 	cw := zerolog.ConsoleWriter{
 		Out:         os.Stderr,
 		NoColor:     true,
@@ -40,8 +67,10 @@ func newMainLogger(level zerolog.Level, bufSize int, poll time.Duration) (*zerol
 		FormatLevel: levelToUpper,
 	}
 	dw := diode.NewWriter(cw, bufSize, poll, nil)
-	l := zerolog.New(dw).Level(level).With().Timestamp().Logger()
-	return &l, dw
+	lw := &levelFilterWriter{out: dw}
+	lw.setLevel(level)
+	l := zerolog.New(lw).Level(zerolog.TraceLevel).With().Timestamp().Logger()
+	return &l, dw, lw
 }
 
 func newEventLogger(f *os.File, bufSize int, poll time.Duration) (*zerolog.Logger, io.Closer) {
@@ -8,7 +8,7 @@ func (app *App) stateLost() appState {
 	if app.dcs.IsConnected() {
 		return stateCandidate
 	}
-	if !app.lostSince.IsZero() && time.Since(app.lostSince) >= app.config.DcsReconnectTimeout {
+	if !app.lostSince.IsZero() && time.Since(app.lostSince) >= app.config().DcsReconnectTimeout {
 		app.logger.Warn().Msgf("Lost state persisted for %s, attempting DCS reconnection", time.Since(app.lostSince).Truncate(time.Second))
 		err := app.reconnectDCS()
 		app.lostSince = time.Now()
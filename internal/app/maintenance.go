@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/yandex/rdsync/internal/dcs"
 )
@@ -30,6 +31,10 @@ func (app *App) leaveMaintenance() error {
 	if err != nil {
 		return err
 	}
+	err = app.refreshNodeConfigurations()
+	if err != nil {
+		return err
+	}
 	state, err := app.getShardStateFromDB()
 	if err != nil {
 		return err
@@ -62,19 +67,19 @@ func (app *App) leaveMaintenance() error {
 }
 
 func (app *App) createMaintenanceFile() {
-	err := os.WriteFile(app.config.MaintenanceFile, []byte(""), 0o640)
+	err := os.WriteFile(app.config().MaintenanceFile, []byte(""), 0o640)
 	if err != nil {
 		app.logger.Error().Err(err).Msg("Failed to write maintenance file")
 	}
 }
 
 func (app *App) doesMaintenanceFileExist() bool {
-	_, err := os.Stat(app.config.MaintenanceFile)
+	_, err := os.Stat(app.config().MaintenanceFile)
 	return err == nil
 }
 
 func (app *App) removeMaintenanceFile() {
-	err := os.Remove(app.config.MaintenanceFile)
+	err := os.Remove(app.config().MaintenanceFile)
 	if err != nil && !os.IsNotExist(err) {
 		app.logger.Error().Err(err).Msg("Failed to remove maintenance file")
 	}
@@ -112,5 +117,34 @@ func (app *App) stateMaintenance() appState {
 		app.removeMaintenanceFile()
 		return stateCandidate
 	}
+	if app.dcs.AcquireLock(pathManagerLock) {
+		app.checkMaintenanceAge(maintenance)
+		app.runScheduledOperations(nil, nil, "", true)
+	}
 	return stateMaintenance
 }
+
+// checkMaintenanceAge schedules leaving expired maintenance and warns about forgotten one
+// once per maintenance_warn_age
+func (app *App) checkMaintenanceAge(maintenance *Maintenance) {
+	if maintenance.Expired() {
+		app.logger.Info().Msgf("Maintenance %s expired, scheduling leave", maintenance)
+		maintenance.ShouldLeave = true
+		err := app.dcs.Set(pathMaintenance, maintenance)
+		if err != nil {
+			app.logger.Error().Err(err).Msg("Failed to schedule leaving expired maintenance")
+		}
+		return
+	}
+	warnAge := app.config().MaintenanceWarnAge
+	age := time.Since(maintenance.InitiatedAt)
+	if warnAge <= 0 || age <= warnAge {
+		return
+	}
+	// previous warning was about another maintenance or long enough ago
+	if !app.maintenanceWarnedFor.Equal(maintenance.InitiatedAt) || time.Since(app.maintenanceWarnedAt) >= warnAge {
+		app.logger.Warn().Msgf("Maintenance %s is active for %s, shard is not managed", maintenance, age.Round(time.Second))
+		app.maintenanceWarnedAt = time.Now()
+		app.maintenanceWarnedFor = maintenance.InitiatedAt
+	}
+}
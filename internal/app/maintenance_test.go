@@ -0,0 +1,55 @@
+package app
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex/rdsync/internal/config"
+)
+
+func TestCheckMaintenanceAge(t *testing.T) {
+	var logs bytes.Buffer
+	logger := zerolog.New(&logs).Level(zerolog.WarnLevel)
+	app := &App{
+		logger:       &logger,
+		dcs:          newTestDCS(),
+		configHolder: config.NewHolder(&config.Config{Hostname: "valkey1", MaintenanceWarnAge: time.Hour}),
+	}
+
+	// forgotten maintenance is reported once per warn age
+	maintenance := &Maintenance{InitiatedAt: time.Now().Add(-2 * time.Hour)}
+	require.NoError(t, app.dcs.Set(pathMaintenance, maintenance))
+	app.checkMaintenanceAge(maintenance)
+	app.checkMaintenanceAge(maintenance)
+	require.Equal(t, 1, strings.Count(logs.String(), "shard is not managed"))
+	app.maintenanceWarnedAt = time.Now().Add(-time.Hour)
+	app.checkMaintenanceAge(maintenance)
+	require.Equal(t, 2, strings.Count(logs.String(), "shard is not managed"))
+
+	// new maintenance is reported regardless of previous one
+	maintenance = &Maintenance{InitiatedAt: time.Now().Add(-time.Hour - time.Minute)}
+	app.checkMaintenanceAge(maintenance)
+	require.Equal(t, 3, strings.Count(logs.String(), "shard is not managed"))
+
+	// fresh maintenance is not reported
+	fresh := &Maintenance{InitiatedAt: time.Now()}
+	app.checkMaintenanceAge(fresh)
+	require.Equal(t, 3, strings.Count(logs.String(), "shard is not managed"))
+	stored, err := app.GetMaintenance()
+	require.NoError(t, err)
+	require.False(t, stored.ShouldLeave)
+
+	// expired maintenance is scheduled to leave
+	expired := &Maintenance{InitiatedAt: time.Now().Add(-time.Minute), ExpiresAt: time.Now().Add(-time.Second)}
+	require.True(t, expired.Expired())
+	require.False(t, fresh.Expired())
+	app.checkMaintenanceAge(expired)
+	stored, err = app.GetMaintenance()
+	require.NoError(t, err)
+	require.True(t, stored.ShouldLeave)
+}
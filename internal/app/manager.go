@@ -21,6 +21,28 @@ func (app *App) stateManager() appState {
 	if err != nil {
 		app.logger.Error().Err(err).Msg("Updating hosts info failed")
 	}
+	err = app.refreshNodeConfigurations()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Failed to get hosts configuration from DCS")
+		return stateManager
+	}
+	if app.isHostInMaintenance(app.config().Hostname) {
+		app.logger.Info().Msg("Local host is under maintenance, releasing manager lock")
+		app.dcs.ReleaseLock(pathManagerLock)
+		return stateCandidate
+	}
+	app.clearExpiredFences()
+	app.clearExpiredFailoverFreeze()
+	localFence, err := app.getFence(app.config().Hostname)
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Failed to get local host fence from DCS")
+		return stateManager
+	}
+	if localFence != nil && !localFence.Expired() {
+		app.logger.Info().Msg("Local host is fenced, releasing manager lock")
+		app.dcs.ReleaseLock(pathManagerLock)
+		return stateCandidate
+	}
 
 	shardState, err := app.getShardStateFromDB()
 	if err != nil {
@@ -33,6 +55,7 @@ func (app *App) stateManager() appState {
 		app.logger.Error().Err(err).Msg("Failed to get shard state from DCS")
 		return stateManager
 	}
+	app.detectSplitBrain(shardState)
 
 	master, err := app.getCurrentMaster(shardState)
 	if err != nil {
@@ -49,6 +72,7 @@ func (app *App) stateManager() appState {
 	app.logger.Info().Msgf("Master: %s", master)
 	app.logger.Info().Msgf("Shard state: %v", shardState)
 	app.logger.Info().Msgf("DCS shard state: %v", shardStateDcs)
+	app.checkSettingsDivergence(shardStateDcs)
 
 	maintenance, err := app.GetMaintenance()
 	if err != nil && !errors.Is(err, dcs.ErrNotFound) {
@@ -71,7 +95,7 @@ func (app *App) stateManager() appState {
 
 	var switchover Switchover
 	if err := app.dcs.Get(pathCurrentSwitch, &switchover); err == nil {
-		if !switchover.InitiatedAt.IsZero() && time.Since(switchover.InitiatedAt) > app.config.Valkey.SwitchoverTimeout {
+		if !switchover.InitiatedAt.IsZero() && time.Since(switchover.InitiatedAt) > app.config().Valkey.SwitchoverTimeout {
 			app.logger.Error().Msgf("Switchover: %s => %s timed out after %s", switchover.From, switchover.To, time.Since(switchover.InitiatedAt))
 			err = app.finishSwitchover(&switchover, fmt.Errorf("switchover timed out after %s", time.Since(switchover.InitiatedAt)))
 			if err != nil {
@@ -79,7 +103,7 @@ func (app *App) stateManager() appState {
 			}
 			return stateManager
 		}
-		err = app.approveSwitchover(&switchover, activeNodes, shardState)
+		err = app.approveSwitchover(&switchover, activeNodes, shardState, master)
 		if err != nil {
 			app.logger.Error().Err(err).Msg("Unable to perform switchover")
 			err = app.finishSwitchover(&switchover, err)
@@ -127,7 +151,12 @@ func (app *App) stateManager() appState {
 			return stateManager
 		}
 	}
-	hosts := len(app.shard.Hosts())
+	observations, err := app.getObservations()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Failed to get observations from DCS")
+	}
+	witnesses := app.getWitnesses(observations)
+	hosts := len(app.shard.Hosts()) + len(witnesses)
 	masterFailed := false
 	if shardStateDcs[master].PingOk && !shardState[master].PingOk {
 		availableReplicas := 0
@@ -138,6 +167,13 @@ func (app *App) stateManager() appState {
 				app.logger.Warn().Str("fqdn", host).Msg("Host seems down")
 			}
 		}
+		for _, witness := range witnesses {
+			// missing entry means witness has no opinion on master yet
+			if reachable, ok := observations[witness].Reachable[master]; ok && !reachable {
+				app.logger.Warn().Str("fqdn", witness).Msg("Witness agrees master seems down")
+				availableReplicas++
+			}
+		}
 		if availableReplicas > hosts/2 {
 			app.logger.Error().Msg("We see that majority of shard is still alive, but master is not. So it probably failed.")
 			masterFailed = true
@@ -186,22 +222,29 @@ func (app *App) stateManager() appState {
 				app.logger.Warn().Str("fqdn", host).Msg("Host seems down in DCS")
 			}
 		}
+		// witnesses are alive according to DCS and visible from here if they see local host
+		for _, witness := range witnesses {
+			availableReplicasDcs++
+			if observations[witness].Reachable[app.config().Hostname] {
+				availableReplicas++
+			}
+		}
 		if availableReplicas <= hosts/2 && availableReplicasDcs > hosts/2 {
 			if app.splitTime[master].IsZero() {
 				app.splitTime[master] = time.Now()
 			}
-			if app.config.Valkey.FailoverTimeout > 0 {
+			if app.config().Valkey.FailoverTimeout > 0 {
 				failedTime := time.Since(app.splitTime[master])
-				if failedTime < app.config.Valkey.FailoverTimeout {
+				if failedTime < app.config().Valkey.FailoverTimeout {
 					app.logger.Error().Msgf(
 						"According to DCS majority of shard is still alive, but we don't see that from here, will wait for %v before giving up on manager role",
-						app.config.Valkey.FailoverTimeout-failedTime)
+						app.config().Valkey.FailoverTimeout-failedTime)
 					return stateManager
 				}
 			}
 			needGiveUp = true
 		}
-	} else if master != app.config.Hostname && !shardState[master].PingOk {
+	} else if master != app.config().Hostname && !shardState[master].PingOk {
 		app.logger.Error().Msgf("Master %s probably failed, do not perform any kind of repair", master)
 		return stateManager
 	}
@@ -209,9 +252,9 @@ func (app *App) stateManager() appState {
 		app.logger.Error().Msg("According to DCS majority of shard is still alive, but we don't see that from here. Giving up on manager role")
 		delete(app.splitTime, master)
 		app.dcs.ReleaseLock(pathManagerLock)
-		waitCtx, cancel := context.WithTimeout(app.ctx, app.config.Valkey.FailoverTimeout)
+		waitCtx, cancel := context.WithTimeout(app.ctx, app.config().Valkey.FailoverTimeout)
 		defer cancel()
-		ticker := time.NewTicker(app.config.TickInterval)
+		ticker := time.NewTicker(app.config().TickInterval)
 		var manager dcs.LockOwner
 	Out:
 		for {
@@ -220,7 +263,7 @@ func (app *App) stateManager() appState {
 				err = app.dcs.Get(pathManagerLock, &manager)
 				if err != nil {
 					app.logger.Error().Err(err).Msgf("Failed to get %s", pathManagerLock)
-				} else if manager.Hostname != app.config.Hostname {
+				} else if manager.Hostname != app.config().Hostname {
 					app.logger.Info().Msgf("New manager: %s", manager.Hostname)
 					break Out
 				}
@@ -239,6 +282,9 @@ func (app *App) stateManager() appState {
 	delete(app.nodeFailTime, master)
 	delete(app.splitTime, master)
 	app.repairShard(shardState, activeNodes, master)
+	app.completeJoins(shardState, master)
+	app.checkSwitchBack(shardState, activeNodes, master)
+	app.runScheduledOperations(shardState, activeNodes, master, false)
 
 	if updateActive {
 		err = app.updateActiveNodes(shardState, shardStateDcs, activeNodes, master)
@@ -8,8 +8,67 @@ import (
 	"github.com/yandex/rdsync/internal/dcs"
 )
 
-func (app *App) getNumReplicasToWrite(activeNodes []string) int {
-	return len(activeNodes) / 2
+const (
+	quorumPolicyMajority  = "majority"
+	quorumPolicyFixed     = "fixed"
+	quorumPolicyAllButOne = "all_but_one"
+	quorumPolicyPerZone   = "per_zone"
+)
+
+func validateReplicationQuorumPolicy(policy string, size int) error {
+	switch policy {
+	case quorumPolicyMajority, quorumPolicyAllButOne, quorumPolicyPerZone:
+		return nil
+	case quorumPolicyFixed:
+		if size < 0 {
+			return fmt.Errorf("replication_quorum_size should not be negative, got %d", size)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown replication quorum policy: %s", policy)
+}
+
+// getNumReplicasToWrite returns number of replicas which should acknowledge write
+// according to configured replication quorum policy
+func (app *App) getNumReplicasToWrite(activeNodes []string, master string) int {
+	voting := app.votingNodes(activeNodes)
+	masterZone := app.hostZone(master)
+	replicas := 0
+	sameZone := 0
+	zoneReplicas := make(map[string]int)
+	for _, host := range voting {
+		if host == master {
+			continue
+		}
+		replicas++
+		zone := app.hostZone(host)
+		if masterZone != "" && zone == masterZone {
+			sameZone++
+		} else if zone != "" {
+			zoneReplicas[zone]++
+		}
+	}
+	switch app.config().Valkey.ReplicationQuorumPolicy {
+	case quorumPolicyFixed:
+		return min(app.config().Valkey.ReplicationQuorumSize, replicas)
+	case quorumPolicyAllButOne:
+		return max(replicas-1, 0)
+	case quorumPolicyPerZone:
+		if len(zoneReplicas) > 0 {
+			// any set of acknowledging replicas of this size includes a host from every other zone
+			smallest := replicas
+			for _, count := range zoneReplicas {
+				smallest = min(smallest, count)
+			}
+			return replicas - smallest + 1
+		}
+	}
+	num := len(voting) / 2
+	// with zones set acknowledging replicas should include a host outside master zone
+	if masterZone != "" && replicas > sameZone && num <= sameZone {
+		num = sameZone + 1
+	}
+	return num
 }
 
 func (app *App) getCurrentMaster(shardState map[string]*HostState) (string, error) {
@@ -106,7 +165,7 @@ func (app *App) changeMaster(host, master string) error {
 
 	app.repairReplica(node, masterState, state, master, host)
 
-	deadline := time.Now().Add(app.config.Valkey.WaitReplicationTimeout)
+	deadline := time.Now().Add(app.config().Valkey.WaitReplicationTimeout)
 	for time.Now().Before(deadline) {
 		state = app.getHostState(host)
 		rs := state.ReplicaState
@@ -138,7 +197,7 @@ func (app *App) waitForCatchup(host, master string) error {
 		return fmt.Errorf("waiting for %s to catchup with itself", host)
 	}
 
-	deadline := time.Now().Add(app.config.Valkey.WaitCatchupTimeout)
+	deadline := time.Now().Add(app.config().Valkey.WaitCatchupTimeout)
 	for time.Now().Before(deadline) {
 		masterState := app.getHostState(master)
 		if !masterState.PingOk {
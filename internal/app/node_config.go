@@ -0,0 +1,554 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/yandex/rdsync/internal/dcs"
+	"github.com/yandex/rdsync/internal/valkey"
+)
+
+// refreshNodeConfigurations caches per-host configurations from dcs for current tick
+func (app *App) refreshNodeConfigurations() error {
+	configs := make(map[string]*valkey.NodeConfiguration)
+	for _, host := range app.shard.Hosts() {
+		nc, err := app.shard.GetNodeConfiguration(host)
+		if err != nil {
+			return err
+		}
+		configs[host] = nc
+	}
+	app.nodeConfigs = configs
+	return nil
+}
+
+// ineligibleReason returns why host could not be active or promoted (empty if it could)
+func (app *App) ineligibleReason(host string) string {
+	nc, ok := app.nodeConfigs[host]
+	switch {
+	case !ok:
+		return ""
+	case nc.Maintenance != nil:
+		return "under maintenance"
+	case nc.Drained:
+		return "drained"
+	case nc.Joining:
+		return "joining"
+	}
+	return ""
+}
+
+// promotionBlocker returns why host could not become master (empty if it could)
+func (app *App) promotionBlocker(host string) string {
+	if reason := app.ineligibleReason(host); reason != "" {
+		return reason
+	}
+	if nc, ok := app.nodeConfigs[host]; ok && nc.NeverPromote {
+		return "never-promote"
+	}
+	return ""
+}
+
+func (app *App) hostZone(host string) string {
+	if nc, ok := app.nodeConfigs[host]; ok {
+		return nc.Zone
+	}
+	return ""
+}
+
+// countZones returns number of zones having alive replicas within nodes and number of all shard zones
+func (app *App) countZones(nodes []string, shardState map[string]*HostState) (int, int) {
+	zones := make(map[string]bool)
+	for _, nc := range app.nodeConfigs {
+		if nc.Zone != "" {
+			zones[nc.Zone] = false
+		}
+	}
+	for _, host := range nodes {
+		zone := app.hostZone(host)
+		if zone != "" && countAliveHAReplicasWithinNodes([]string{host}, shardState) > 0 {
+			zones[zone] = true
+		}
+	}
+	alive := 0
+	for _, ok := range zones {
+		if ok {
+			alive++
+		}
+	}
+	return alive, len(zones)
+}
+
+type zoneHealth struct {
+	name  string
+	alive int
+	total int
+}
+
+// zoneHealth returns number of alive and all hosts per zone
+func (app *App) zoneHealth(shardState map[string]*HostState) []zoneHealth {
+	byName := make(map[string]*zoneHealth)
+	for host, nc := range app.nodeConfigs {
+		if nc.Zone == "" {
+			continue
+		}
+		zone, ok := byName[nc.Zone]
+		if !ok {
+			zone = &zoneHealth{name: nc.Zone}
+			byName[nc.Zone] = zone
+		}
+		zone.total++
+		if state, ok := shardState[host]; ok && state.PingOk {
+			zone.alive++
+		}
+	}
+	zones := make([]zoneHealth, 0, len(byName))
+	for _, zone := range byName {
+		zones = append(zones, *zone)
+	}
+	sort.Slice(zones, func(i, j int) bool { return zones[i].name < zones[j].name })
+	return zones
+}
+
+func (app *App) isHostVoting(host string) bool {
+	nc, ok := app.nodeConfigs[host]
+	return !ok || !nc.NonVoting
+}
+
+// votingNodes returns nodes counted in replicas to write and failover quorum
+func (app *App) votingNodes(nodes []string) []string {
+	voting := make([]string, 0, len(nodes))
+	for _, host := range nodes {
+		if app.isHostVoting(host) {
+			voting = append(voting, host)
+		}
+	}
+	return voting
+}
+
+// updateNodeConfiguration changes per-host configuration in dcs keeping other settings
+func (app *App) updateNodeConfiguration(host string, update func(*valkey.NodeConfiguration)) error {
+	hosts, err := app.shard.GetShardHostsFromDcs()
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(hosts, host) {
+		return fmt.Errorf("host %s is not in shard", host)
+	}
+	// versioned write keeps concurrent cli and manager updates of the same host
+	return dcs.UpdateExisting(app.dcs, dcs.JoinPath(pathHANodes, host), func(nc *valkey.NodeConfiguration) error {
+		update(nc)
+		return nil
+	})
+}
+
+// parseHostsDocument parses yaml document of host -> configuration.
+// Omitted fields get default values.
+func parseHostsDocument(data []byte) (map[string]*valkey.NodeConfiguration, error) {
+	var doc map[string]any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	configs := make(map[string]*valkey.NodeConfiguration, len(doc))
+	for host, value := range doc {
+		nc := valkey.DefaultNodeConfiguration()
+		if value != nil {
+			raw, err := yaml.Marshal(value)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", host, err)
+			}
+			if err := yaml.UnmarshalStrict(raw, nc); err != nil {
+				return nil, fmt.Errorf("%s: %w", host, err)
+			}
+		}
+		if nc.Priority < 0 {
+			return nil, fmt.Errorf("%s: priority must be >= 0, got %d", host, nc.Priority)
+		}
+		configs[host] = nc
+	}
+	return configs, nil
+}
+
+func nodeConfigurationFields(nc *valkey.NodeConfiguration) (map[string]string, error) {
+	fields := make(map[string]string)
+	if nc == nil {
+		return fields, nil
+	}
+	raw, err := yaml.Marshal(nc)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]any
+	if err := yaml.Unmarshal(raw, &values); err != nil {
+		return nil, err
+	}
+	for key, value := range values {
+		fields[key] = fmt.Sprint(value)
+	}
+	return fields, nil
+}
+
+// diffNodeConfigurations returns human-readable changes turning current configurations into desired ones.
+// Hosts missing in desired are reported as removed only if prune is set.
+func diffNodeConfigurations(current, desired map[string]*valkey.NodeConfiguration, prune bool) ([]string, error) {
+	hosts := make([]string, 0, len(current)+len(desired))
+	for host := range current {
+		hosts = append(hosts, host)
+	}
+	for host := range desired {
+		if _, ok := current[host]; !ok {
+			hosts = append(hosts, host)
+		}
+	}
+	sort.Strings(hosts)
+	var changes []string
+	for _, host := range hosts {
+		currentConf, inCurrent := current[host]
+		desiredConf, inDesired := desired[host]
+		switch {
+		case !inDesired && prune:
+			changes = append(changes, fmt.Sprintf("- %s", host))
+		case !inDesired:
+			continue
+		case !inCurrent:
+			changes = append(changes, fmt.Sprintf("+ %s", host))
+		default:
+			currentFields, err := nodeConfigurationFields(currentConf)
+			if err != nil {
+				return nil, err
+			}
+			desiredFields, err := nodeConfigurationFields(desiredConf)
+			if err != nil {
+				return nil, err
+			}
+			keys := make([]string, 0, len(currentFields)+len(desiredFields))
+			for key := range currentFields {
+				keys = append(keys, key)
+			}
+			for key := range desiredFields {
+				if _, ok := currentFields[key]; !ok {
+					keys = append(keys, key)
+				}
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				if currentFields[key] != desiredFields[key] {
+					changes = append(changes, fmt.Sprintf("~ %s %s: %q -> %q", host, key, currentFields[key], desiredFields[key]))
+				}
+			}
+		}
+	}
+	return changes, nil
+}
+
+func (app *App) getNodeConfigurations() (map[string]*valkey.NodeConfiguration, error) {
+	hosts, err := app.shard.GetShardHostsFromDcs()
+	if err != nil {
+		return nil, err
+	}
+	configs := make(map[string]*valkey.NodeConfiguration, len(hosts))
+	for _, host := range hosts {
+		nc, err := app.shard.GetNodeConfiguration(host)
+		if err != nil {
+			return nil, err
+		}
+		configs[host] = nc
+	}
+	return configs, nil
+}
+
+// CliHostShow prints configuration and health of shard hosts
+func (app *App) CliHostShow(host string) int {
+	err := app.connectDCS()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Unable to connect to dcs")
+		return 1
+	}
+	defer app.dcs.Close()
+	app.dcs.Initialize()
+	app.shard = valkey.NewShard(app.configHolder, app.logger, app.dcs)
+	defer app.shard.Close()
+	if err := app.shard.UpdateHostsInfo(); err != nil {
+		app.logger.Error().Err(err).Msg("Unable to update hosts info")
+		return 1
+	}
+
+	configs, err := app.getNodeConfigurations()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Failed to get hosts configuration")
+		return 1
+	}
+	if host != "" {
+		nc, ok := configs[host]
+		if !ok {
+			app.logger.Error().Msgf("Host %s is not in shard", host)
+			return 1
+		}
+		configs = map[string]*valkey.NodeConfiguration{host: nc}
+	}
+	shardState, err := app.getShardStateFromDcs()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Failed to get shard state")
+		return 1
+	}
+	activeNodes, err := app.GetActiveNodes()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Failed to get active nodes")
+		return 1
+	}
+	var master string
+	err = app.dcs.Get(pathMasterNode, &master)
+	if err != nil && !errors.Is(err, dcs.ErrNotFound) {
+		app.logger.Error().Err(err).Msgf("Failed to get %s", pathMasterNode)
+		return 1
+	}
+
+	data := make(map[string]any, len(configs))
+	for name, nc := range configs {
+		health := "unknown"
+		if state, ok := shardState[name]; ok && !state.CheckAt.IsZero() {
+			health = state.String()
+		}
+		data[name] = map[string]any{
+			"config": nc,
+			"health": health,
+			"active": slices.Contains(activeNodes, name),
+			"master": name == master,
+		}
+	}
+	out, err := yaml.Marshal(data)
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Failed to marshal yaml")
+		return 1
+	}
+	fmt.Print(string(out))
+	return 0
+}
+
+// CliHostExport prints configuration of all shard hosts as yaml document accepted by import
+func (app *App) CliHostExport() int {
+	err := app.connectDCS()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Unable to connect to dcs")
+		return 1
+	}
+	defer app.dcs.Close()
+	app.dcs.Initialize()
+	app.shard = valkey.NewShard(app.configHolder, app.logger, app.dcs)
+	defer app.shard.Close()
+
+	configs, err := app.getNodeConfigurations()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Failed to get hosts configuration")
+		return 1
+	}
+	out, err := yaml.Marshal(configs)
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Failed to marshal yaml")
+		return 1
+	}
+	fmt.Print(string(out))
+	return 0
+}
+
+// keepManagerOwnedFields copies fields changed by manager only from stored configurations
+// into desired ones of the same hosts
+func keepManagerOwnedFields(current, desired map[string]*valkey.NodeConfiguration) {
+	for host, nc := range desired {
+		if stored, ok := current[host]; ok {
+			nc.Joining = stored.Joining
+		}
+	}
+}
+
+// CliHostImport applies yaml document of host configurations to dcs.
+// New hosts are checked like in host add, manager-owned fields of existing hosts are kept.
+// Exit codes match host add: 0 - no changes, 1 - error, 2 - changes detected in dry run.
+func (app *App) CliHostImport(file string, prune, dryRun, skipValkeyCheck bool) int {
+	var data []byte
+	var err error
+	if file == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		app.logger.Error().Err(err).Msgf("Unable to read %s", file)
+		return 1
+	}
+	desired, err := parseHostsDocument(data)
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Malformed hosts document")
+		return 1
+	}
+
+	err = app.connectDCS()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Unable to connect to dcs")
+		return 1
+	}
+	defer app.dcs.Close()
+	app.dcs.Initialize()
+	app.refreshShardConfig()
+	app.shard = valkey.NewShard(app.configHolder, app.logger, app.dcs)
+	defer app.shard.Close()
+
+	current, err := app.getNodeConfigurations()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Failed to get hosts configuration")
+		return 1
+	}
+	keepManagerOwnedFields(current, desired)
+	changes, err := diffNodeConfigurations(current, desired, prune)
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Failed to compare hosts configuration")
+		return 1
+	}
+	for host := range current {
+		if _, ok := desired[host]; !ok && !prune {
+			app.logger.Warn().Msgf("%s is not in document, keeping it (use --prune to remove)", host)
+		}
+	}
+	if len(changes) == 0 {
+		fmt.Println("no changes detected")
+		return 0
+	}
+	for _, change := range changes {
+		fmt.Println(change)
+	}
+	var pruned []string
+	if prune {
+		for host := range current {
+			if _, ok := desired[host]; !ok {
+				pruned = append(pruned, host)
+			}
+		}
+		sort.Strings(pruned)
+	}
+	if len(pruned) > 0 {
+		if err := app.shard.UpdateHostsInfo(); err != nil {
+			app.logger.Error().Err(err).Msg("Unable to update hosts info")
+			return 1
+		}
+		if err := app.refreshNodeConfigurations(); err != nil {
+			app.logger.Error().Err(err).Msg("Unable to get hosts configuration")
+			return 1
+		}
+		prohibited, err := app.removalBlockers(pruned)
+		if err != nil {
+			app.logger.Error().Err(err).Msg("Unable to check hosts removal")
+			return 1
+		}
+		for _, reason := range prohibited {
+			app.logger.Error().Msgf("Unable to prune hosts: %s", reason)
+		}
+		if len(prohibited) > 0 {
+			return 1
+		}
+	}
+	var added []string
+	for host := range desired {
+		if _, ok := current[host]; !ok {
+			added = append(added, host)
+		}
+	}
+	sort.Strings(added)
+	if !skipValkeyCheck {
+		for _, host := range added {
+			if err := app.checkHostAlive(host); err != nil {
+				app.logger.Error().Err(err).Msg("Unable to import hosts")
+				return 1
+			}
+		}
+	}
+	if dryRun {
+		return 2
+	}
+
+	err = app.dcs.Create(dcs.JoinPath(pathHANodes), nil)
+	if err != nil && !errors.Is(err, dcs.ErrExists) {
+		app.logger.Error().Err(err).Msg("Unable to create hosts path in dcs")
+		return 1
+	}
+	for host, nc := range desired {
+		err = app.importNodeConfiguration(host, nc, slices.Contains(added, host))
+		if err != nil {
+			app.logger.Error().Err(err).Msgf("Unable to set configuration of %s", host)
+			return 1
+		}
+	}
+	for _, host := range pruned {
+		if err := app.removeHost(host); err != nil {
+			app.logger.Error().Err(err).Msgf("Unable to remove %s", host)
+			return 1
+		}
+	}
+	fmt.Println("hosts configuration imported")
+	return 0
+}
+
+// importNodeConfiguration creates configuration of new host
+// or updates existing one keeping fields owned by manager
+func (app *App) importNodeConfiguration(host string, desired *valkey.NodeConfiguration, added bool) error {
+	path := dcs.JoinPath(pathHANodes, host)
+	if added {
+		return app.dcs.Create(path, desired)
+	}
+	return dcs.UpdateExisting(app.dcs, path, func(nc *valkey.NodeConfiguration) error {
+		joining := nc.Joining
+		*nc = *desired
+		nc.Joining = joining
+		return nil
+	})
+}
+
+// CliHostSet changes individual configuration fields of a host
+func (app *App) CliHostSet(host string, priority *int, drained, neverPromote, nonVoting *bool, zone *string) int {
+	if priority == nil && drained == nil && neverPromote == nil && nonVoting == nil && zone == nil {
+		app.logger.Error().Msg("Nothing to set")
+		return 1
+	}
+	if priority != nil && *priority < 0 {
+		app.logger.Error().Msgf("Priority must be >= 0. Got: %d", *priority)
+		return 1
+	}
+	err := app.connectDCS()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Unable to connect to dcs")
+		return 1
+	}
+	defer app.dcs.Close()
+	app.dcs.Initialize()
+	app.shard = valkey.NewShard(app.configHolder, app.logger, app.dcs)
+	defer app.shard.Close()
+
+	err = app.updateNodeConfiguration(host, func(nc *valkey.NodeConfiguration) {
+		if priority != nil {
+			nc.Priority = *priority
+		}
+		if drained != nil {
+			nc.Drained = *drained
+		}
+		if neverPromote != nil {
+			nc.NeverPromote = *neverPromote
+		}
+		if nonVoting != nil {
+			nc.NonVoting = *nonVoting
+		}
+		if zone != nil {
+			nc.Zone = *zone
+		}
+	})
+	if err != nil {
+		app.logger.Error().Err(err).Msgf("Unable to update configuration of %s", host)
+		return 1
+	}
+	fmt.Println("host configuration updated")
+	return 0
+}
@@ -0,0 +1,188 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex/rdsync/internal/config"
+	"github.com/yandex/rdsync/internal/dcs"
+	"github.com/yandex/rdsync/internal/valkey"
+)
+
+func TestParseHostsDocument(t *testing.T) {
+	configs, err := parseHostsDocument([]byte("valkey1:\n  priority: 200\nvalkey2:\nvalkey3:\n  drained: true\n"))
+	require.NoError(t, err)
+	require.Equal(t, map[string]*valkey.NodeConfiguration{
+		"valkey1": {Priority: 200},
+		"valkey2": {Priority: 100},
+		"valkey3": {Priority: 100, Drained: true},
+	}, configs)
+
+	_, err = parseHostsDocument([]byte("valkey1:\n  priorty: 200\n"))
+	require.Error(t, err)
+	_, err = parseHostsDocument([]byte("valkey1:\n  priority: -1\n"))
+	require.Error(t, err)
+}
+
+func TestDiffNodeConfigurations(t *testing.T) {
+	current := map[string]*valkey.NodeConfiguration{
+		"valkey1": {Priority: 100},
+		"valkey2": {Priority: 100},
+		"valkey3": {Priority: 100},
+	}
+	desired := map[string]*valkey.NodeConfiguration{
+		"valkey1": {Priority: 100},
+		"valkey2": {Priority: 200, Drained: true},
+		"valkey4": {Priority: 100},
+	}
+	changes, err := diffNodeConfigurations(current, desired, false)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		`~ valkey2 drained: "" -> "true"`,
+		`~ valkey2 priority: "100" -> "200"`,
+		"+ valkey4",
+	}, changes)
+	changes, err = diffNodeConfigurations(current, desired, true)
+	require.NoError(t, err)
+	require.Contains(t, changes, "- valkey3")
+	changes, err = diffNodeConfigurations(current, current, true)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+}
+
+func TestNonVotingQuorum(t *testing.T) {
+	app := &App{configHolder: config.NewHolder(&config.Config{})}
+	activeNodes := []string{"valkey1", "valkey2", "valkey3", "valkey4"}
+	require.Equal(t, 2, app.getNumReplicasToWrite(activeNodes, "valkey1"))
+	require.Equal(t, 2, app.getFailoverQuorum(activeNodes, "valkey1"))
+
+	app.nodeConfigs = map[string]*valkey.NodeConfiguration{
+		"valkey3": {Priority: 100, NonVoting: true},
+		"valkey4": {Priority: 0, NeverPromote: true, NonVoting: true},
+	}
+	require.Equal(t, 1, app.getNumReplicasToWrite(activeNodes, "valkey1"))
+	require.Equal(t, 1, app.getFailoverQuorum(activeNodes, "valkey1"))
+	require.Equal(t, []string{"valkey1", "valkey2"}, app.votingNodes(activeNodes))
+	require.Equal(t, "never-promote", app.promotionBlocker("valkey4"))
+	require.Empty(t, app.promotionBlocker("valkey3"))
+}
+
+func TestZoneAwareQuorum(t *testing.T) {
+	app := &App{configHolder: config.NewHolder(&config.Config{})}
+	app.nodeConfigs = map[string]*valkey.NodeConfiguration{
+		"valkey1": {Priority: 100, Zone: "a"},
+		"valkey2": {Priority: 100, Zone: "a"},
+		"valkey3": {Priority: 100, Zone: "a"},
+		"valkey4": {Priority: 100, Zone: "b"},
+		"valkey5": {Priority: 100, Zone: "c"},
+	}
+	activeNodes := []string{"valkey1", "valkey2", "valkey3", "valkey4", "valkey5"}
+	// 2 replicas share master zone, so 3 acks are required to reach another zone
+	require.Equal(t, 3, app.getNumReplicasToWrite(activeNodes, "valkey1"))
+	require.Equal(t, 2, app.getNumReplicasToWrite(activeNodes, "valkey4"))
+
+	alive := &HostState{PingOk: true, PingStable: true, ReplicaState: &ReplicaState{}}
+	shardState := map[string]*HostState{"valkey2": alive, "valkey3": alive, "valkey4": {}, "valkey5": {}}
+	aliveZones, totalZones := app.countZones(activeNodes, shardState)
+	require.Equal(t, 1, aliveZones)
+	require.Equal(t, 3, totalZones)
+
+	require.Equal(t, []zoneHealth{{name: "a", alive: 2, total: 3}, {name: "b", total: 1}, {name: "c", total: 1}},
+		app.zoneHealth(map[string]*HostState{"valkey1": {PingOk: true}, "valkey2": {PingOk: true}}))
+}
+
+func TestReplicationQuorumPolicies(t *testing.T) {
+	app := &App{configHolder: config.NewHolder(&config.Config{})}
+	app.nodeConfigs = map[string]*valkey.NodeConfiguration{
+		"valkey1": {Priority: 100, Zone: "a"},
+		"valkey2": {Priority: 100, Zone: "a"},
+		"valkey3": {Priority: 100, Zone: "b"},
+		"valkey4": {Priority: 100, Zone: "b"},
+		"valkey5": {Priority: 100, Zone: "c"},
+	}
+	activeNodes := []string{"valkey1", "valkey2", "valkey3", "valkey4", "valkey5"}
+
+	updateTestConfig(app, func(conf *config.Config) { conf.Valkey.ReplicationQuorumPolicy = quorumPolicyMajority })
+	require.Equal(t, 2, app.getNumReplicasToWrite(activeNodes, "valkey1"))
+
+	updateTestConfig(app, func(conf *config.Config) {
+		conf.Valkey.ReplicationQuorumPolicy = quorumPolicyFixed
+		conf.Valkey.ReplicationQuorumSize = 1
+	})
+	require.Equal(t, 1, app.getNumReplicasToWrite(activeNodes, "valkey1"))
+	require.Equal(t, 4, app.getFailoverQuorum(activeNodes, "valkey1"))
+	updateTestConfig(app, func(conf *config.Config) { conf.Valkey.ReplicationQuorumSize = 10 })
+	require.Equal(t, 4, app.getNumReplicasToWrite(activeNodes, "valkey1"))
+
+	updateTestConfig(app, func(conf *config.Config) { conf.Valkey.ReplicationQuorumPolicy = quorumPolicyAllButOne })
+	require.Equal(t, 3, app.getNumReplicasToWrite(activeNodes, "valkey1"))
+
+	// zone c has a single replica, so all others should acknowledge before it is guaranteed
+	updateTestConfig(app, func(conf *config.Config) { conf.Valkey.ReplicationQuorumPolicy = quorumPolicyPerZone })
+	require.Equal(t, 4, app.getNumReplicasToWrite(activeNodes, "valkey1"))
+	require.Equal(t, 3, app.getNumReplicasToWrite(activeNodes, "valkey5"))
+
+	require.Error(t, validateReplicationQuorumPolicy("unknown", 0))
+	require.Error(t, validateReplicationQuorumPolicy(quorumPolicyFixed, -1))
+}
+
+func TestUpdateNodeConfigurationConcurrent(t *testing.T) {
+	testDcs := newTestDCS()
+	app := &App{
+		logger:       testLogger(),
+		dcs:          testDcs,
+		configHolder: config.NewHolder(&config.Config{Hostname: "valkey1"}),
+	}
+	app.shard = valkey.NewShard(app.configHolder, app.logger, app.dcs)
+	path := dcs.JoinPath(pathHANodes, "valkey2")
+	require.NoError(t, app.dcs.Set(path, &valkey.NodeConfiguration{Priority: 100, Joining: true}))
+
+	// cli changes priority while manager completes join
+	testDcs.beforeSetVersioned = func() {
+		require.NoError(t, app.updateNodeConfiguration("valkey2", func(nc *valkey.NodeConfiguration) {
+			nc.Priority = 5
+		}))
+	}
+	require.NoError(t, app.updateNodeConfiguration("valkey2", func(nc *valkey.NodeConfiguration) {
+		nc.Joining = false
+	}))
+	var stored valkey.NodeConfiguration
+	require.NoError(t, app.dcs.Get(path, &stored))
+	require.Equal(t, 5, stored.Priority)
+	require.False(t, stored.Joining)
+
+	require.Error(t, app.updateNodeConfiguration("valkey3", func(nc *valkey.NodeConfiguration) {}))
+}
+
+func TestImportKeepsManagerOwnedFields(t *testing.T) {
+	app := &App{
+		logger:       testLogger(),
+		dcs:          newTestDCS(),
+		configHolder: config.NewHolder(&config.Config{Hostname: "valkey1"}),
+	}
+	current := map[string]*valkey.NodeConfiguration{"valkey2": {Priority: 100, Joining: true}}
+	desired := map[string]*valkey.NodeConfiguration{
+		"valkey2": {Priority: 50},
+		"valkey3": {Priority: 100, Joining: true},
+	}
+	keepManagerOwnedFields(current, desired)
+	require.True(t, desired["valkey2"].Joining)
+	require.True(t, desired["valkey3"].Joining)
+	changes, err := diffNodeConfigurations(current, desired, false)
+	require.NoError(t, err)
+	require.Equal(t, []string{`~ valkey2 priority: "100" -> "50"`, "+ valkey3"}, changes)
+
+	path := dcs.JoinPath(pathHANodes, "valkey2")
+	require.NoError(t, app.dcs.Set(path, current["valkey2"]))
+	// import does not reset staged join
+	require.NoError(t, app.importNodeConfiguration("valkey2", &valkey.NodeConfiguration{Priority: 50}, false))
+	var stored valkey.NodeConfiguration
+	require.NoError(t, app.dcs.Get(path, &stored))
+	require.Equal(t, 50, stored.Priority)
+	require.True(t, stored.Joining)
+
+	require.NoError(t, app.importNodeConfiguration("valkey3", desired["valkey3"], true))
+	require.Error(t, app.importNodeConfiguration("valkey3", desired["valkey3"], true))
+	require.Error(t, app.importNodeConfiguration("valkey4", desired["valkey3"], false))
+}
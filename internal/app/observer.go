@@ -0,0 +1,157 @@
+package app
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/yandex/rdsync/internal/config"
+	"github.com/yandex/rdsync/internal/dcs"
+	"github.com/yandex/rdsync/internal/valkey"
+)
+
+// observationsNeeded checks if observations of local host are used by failover decisions
+func (app *App) observationsNeeded() bool {
+	return app.config().Witness || app.config().Valkey.FailoverObserverQuorum > 0
+}
+
+// observer periodically publishes reachability of other shard members as seen from local host.
+// It uses its own connections as shard nodes are used and closed by main loop concurrently.
+func (app *App) observer() {
+	ticker := time.NewTicker(app.config().HealthCheckInterval)
+	defer ticker.Stop()
+	path := dcs.JoinPath(pathObservationsPrefix, app.config().Hostname)
+	nodes := make(map[string]*valkey.Node)
+	var nodesConfig *config.Config
+	closeNodes := func() {
+		for host, node := range nodes {
+			node.Close()
+			delete(nodes, host)
+		}
+	}
+	defer closeNodes()
+	published := false
+	for {
+		select {
+		case <-ticker.C:
+			if !app.observationsNeeded() {
+				if published {
+					closeNodes()
+					if err := app.dcs.Delete(path); err != nil {
+						app.logger.Error().Err(err).Msg("Failed to drop observation from dcs")
+						continue
+					}
+					published = false
+				}
+				continue
+			}
+			// connection settings could change with new config snapshot
+			if conf := app.config(); conf != nodesConfig {
+				closeNodes()
+				nodesConfig = conf
+			}
+			hosts := app.shard.Hosts()
+			for host, node := range nodes {
+				if !slices.Contains(hosts, host) {
+					node.Close()
+					delete(nodes, host)
+				}
+			}
+			observation := Observation{Reachable: make(map[string]bool), Witness: app.config().Witness}
+			for _, host := range hosts {
+				if host == app.config().Hostname {
+					continue
+				}
+				node, ok := nodes[host]
+				if !ok {
+					var err error
+					node, err = valkey.NewNode(app.configHolder, app.logger, host)
+					if err != nil {
+						app.logger.Error().Err(err).Msgf("Observer: unable to create node for %s", host)
+						continue
+					}
+					nodes[host] = node
+				}
+				observation.Reachable[host] = node.Ping(app.ctx) == nil
+			}
+			observation.CheckAt = time.Now()
+			err := app.dcs.SetEphemeral(path, &observation)
+			if err != nil {
+				app.logger.Error().Err(err).Msg("Failed to set observation to dcs")
+				continue
+			}
+			published = true
+		case <-app.ctx.Done():
+			return
+		}
+	}
+}
+
+func (app *App) getObservations() (map[string]*Observation, error) {
+	observers, err := app.dcs.GetChildren(pathObservationsPrefix)
+	if errors.Is(err, dcs.ErrNotFound) {
+		return map[string]*Observation{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	observations := make(map[string]*Observation, len(observers))
+	for _, observer := range observers {
+		var observation Observation
+		err = app.dcs.Get(dcs.JoinPath(pathObservationsPrefix, observer), &observation)
+		if errors.Is(err, dcs.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		observations[observer] = &observation
+	}
+	return observations, nil
+}
+
+// countObservers returns number of observers with fresh observation of host
+// and number of them seeing host unreachable
+func (app *App) countObservers(observations map[string]*Observation, host string) (down, total int) {
+	maxAge := 5 * app.config().HealthCheckInterval
+	for observer, observation := range observations {
+		if observer == host || time.Since(observation.CheckAt) > maxAge {
+			continue
+		}
+		reachable, ok := observation.Reachable[host]
+		if !ok {
+			continue
+		}
+		total++
+		if !reachable {
+			down++
+		}
+	}
+	return down, total
+}
+
+// stateWitness keeps list of data nodes up to date for observer.
+// Witness has no local valkey so it never acquires manager lock and could not be promoted.
+func (app *App) stateWitness() appState {
+	if !app.dcs.IsConnected() {
+		return stateWitness
+	}
+	err := app.shard.UpdateHostsInfo()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Witness: failed to update host info from DCS")
+	}
+	return stateWitness
+}
+
+// getWitnesses returns witness hosts with fresh observations
+func (app *App) getWitnesses(observations map[string]*Observation) []string {
+	maxAge := 5 * app.config().HealthCheckInterval
+	var witnesses []string
+	for observer, observation := range observations {
+		if observation.Witness && time.Since(observation.CheckAt) <= maxAge {
+			witnesses = append(witnesses, observer)
+		}
+	}
+	slices.Sort(witnesses)
+	return witnesses
+}
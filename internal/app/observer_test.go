@@ -0,0 +1,37 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex/rdsync/internal/config"
+)
+
+func TestCountObservers(t *testing.T) {
+	app := &App{configHolder: config.NewHolder(&config.Config{HealthCheckInterval: time.Second})}
+	now := time.Now()
+	observations := map[string]*Observation{
+		"master": {CheckAt: now, Reachable: map[string]bool{"r1": true}},
+		"r1":     {CheckAt: now, Reachable: map[string]bool{"master": false, "r2": true}},
+		"r2":     {CheckAt: now, Reachable: map[string]bool{"master": true, "r1": true}},
+		"r3":     {CheckAt: now.Add(-time.Minute), Reachable: map[string]bool{"master": false}},
+		"r4":     {CheckAt: now, Reachable: map[string]bool{"r1": true}},
+	}
+	down, total := app.countObservers(observations, "master")
+	require.Equal(t, 1, down)
+	require.Equal(t, 2, total)
+}
+
+func TestGetWitnesses(t *testing.T) {
+	app := &App{configHolder: config.NewHolder(&config.Config{HealthCheckInterval: time.Second})}
+	now := time.Now()
+	observations := map[string]*Observation{
+		"w2": {CheckAt: now, Witness: true},
+		"w1": {CheckAt: now, Witness: true},
+		"w3": {CheckAt: now.Add(-time.Minute), Witness: true},
+		"r1": {CheckAt: now},
+	}
+	require.Equal(t, []string{"w1", "w2"}, app.getWitnesses(observations))
+}
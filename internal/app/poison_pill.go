@@ -14,21 +14,32 @@ func (app *App) getPoisonPill() (*PoisonPill, error) {
 	return &poisonPill, err
 }
 
-func (app *App) issuePoisonPill(targetHost string) error {
+func (app *App) issuePoisonPill(targetHost, cause string) error {
 	poisonPill := &PoisonPill{
 		TargetHost:  targetHost,
-		InitiatedBy: app.config.Hostname,
+		InitiatedBy: app.config().Hostname,
 		InitiatedAt: time.Now(),
+		Cause:       cause,
 	}
 
 	return app.dcs.Create(pathPoisonPill, poisonPill)
 }
 
 func (app *App) applyPoisonPill(poisonPill *PoisonPill) error {
-	if poisonPill.TargetHost != app.config.Hostname {
+	if poisonPill.TargetHost != app.config().Hostname {
 		app.logger.Info().Msgf("Poison pill issued for %s: not local host", poisonPill.TargetHost)
 		return nil
 	}
+	err := app.setLocalOffline(poisonPill)
+	if err != nil {
+		return err
+	}
+	poisonPill.Applied = true
+	return app.dcs.Set(pathPoisonPill, poisonPill)
+}
+
+// setLocalOffline makes local node offline (or restarts it if its state is unknown)
+func (app *App) setLocalOffline(poisonPill *PoisonPill) error {
 	local := app.shard.Local()
 	isOffline, err := local.IsOffline(app.ctx)
 	if err != nil {
@@ -36,21 +47,18 @@ func (app *App) applyPoisonPill(poisonPill *PoisonPill) error {
 		return local.Restart(app.ctx)
 	}
 	if !isOffline {
-		app.logger.Info().Msgf("Applying poison pill issued by %s: Going offline", poisonPill.InitiatedBy)
-		err = local.SetOffline(app.ctx)
-		if err != nil {
-			return err
-		}
+		app.logger.Info().Msgf("Applying poison pill issued by %s (%s): Going offline", poisonPill.InitiatedBy, poisonPill.Cause)
+		return local.SetOffline(app.ctx)
 	}
-	poisonPill.Applied = true
-	return app.dcs.Set(pathPoisonPill, poisonPill)
+	return nil
 }
 
 func (app *App) clearPoisonPill() error {
 	return app.dcs.Delete(pathPoisonPill)
 }
 
-func (app *App) waitPoisonPill(timeout time.Duration) {
+// waitPoisonPill waits for target host to apply poison pill and returns true if it was applied
+func (app *App) waitPoisonPill(timeout time.Duration) bool {
 	waitCtx, cancel := context.WithTimeout(app.ctx, timeout)
 	defer cancel()
 	var poisonPill PoisonPill
@@ -77,4 +85,5 @@ Out:
 	if !poisonPill.Applied {
 		app.logger.Error().Msgf("Poison pill for %s was not applied within timeout", poisonPill.TargetHost)
 	}
+	return poisonPill.Applied
 }
@@ -7,7 +7,7 @@ import (
 )
 
 func (app *App) pprofHandler() {
-	if app.config.PprofAddr == "" {
+	if app.config().PprofAddr == "" {
 		return
 	}
 	serverMux := http.NewServeMux()
@@ -18,8 +18,9 @@ func (app *App) pprofHandler() {
 	serverMux.HandleFunc("/pprof/trace", pprof.Trace)
 	serverMux.HandleFunc("/pprof/heap", pprof.Handler("heap").ServeHTTP)
 	serverMux.HandleFunc("/pprof/goroutine", pprof.Handler("goroutine").ServeHTTP)
+	serverMux.HandleFunc("/config/reload", app.configReloadHandler)
 
-	err := http.ListenAndServe(app.config.PprofAddr, serverMux)
+	err := http.ListenAndServe(app.config().PprofAddr, serverMux)
 	if err != nil {
 		app.logger.Error().Err(err).Msg("Unable to init pprof handler")
 		os.Exit(1)
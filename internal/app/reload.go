@@ -0,0 +1,170 @@
+package app
+
+import (
+	json "encoding/json/v2"
+	"net/http"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/yandex/rdsync/internal/config"
+)
+
+// ConfigReloadResult contains outcome of config reload
+type ConfigReloadResult struct {
+	ReloadedAt      time.Time `json:"reloaded_at" yaml:"reloaded_at"`
+	Error           string    `json:"error" yaml:"error"`
+	Applied         []string  `json:"applied" yaml:"applied"`
+	RestartRequired []string  `json:"restart_required" yaml:"restart_required"`
+}
+
+// keepRestartRequired resets values which could not be applied without restart to old ones
+func keepRestartRequired(old, updated *config.Config) {
+	updated.Hostname = old.Hostname
+	updated.Mode = old.Mode
+	updated.Witness = old.Witness
+	updated.DaemonLockFile = old.DaemonLockFile
+	updated.PprofAddr = old.PprofAddr
+	updated.InfoFile = old.InfoFile
+	updated.EventTimingLogFile = old.EventTimingLogFile
+	updated.LogBufferSize = old.LogBufferSize
+	updated.LogPollInterval = old.LogPollInterval
+	updated.HealthCheckInterval = old.HealthCheckInterval
+	updated.InfoFileHandlerInterval = old.InfoFileHandlerInterval
+	// DCS reconnect drops manager lock and health node
+	updated.Zookeeper = old.Zookeeper
+	updated.Valkey.Port = old.Valkey.Port
+	updated.Valkey.ClusterBusPort = old.Valkey.ClusterBusPort
+	updated.SentinelMode.Name = old.SentinelMode.Name
+	updated.SentinelMode.RunID = old.SentinelMode.RunID
+	updated.SentinelMode.CachePort = old.SentinelMode.CachePort
+}
+
+// splitConfigChanges returns config with applicable changes only
+// and lists of applied and restart-requiring changed keys
+func splitConfigChanges(old, loaded *config.Config) (*config.Config, []string, []string) {
+	changed := config.Diff(old, loaded)
+	updated := *loaded
+	keepRestartRequired(old, &updated)
+	applied := config.Diff(old, &updated)
+	var restartRequired []string
+	for _, key := range changed {
+		if !slices.Contains(applied, key) {
+			restartRequired = append(restartRequired, key)
+		}
+	}
+	return &updated, applied, restartRequired
+}
+
+func changedAny(changed []string, keys ...string) bool {
+	for _, key := range keys {
+		if slices.Contains(changed, key) {
+			return true
+		}
+	}
+	return false
+}
+
+func (app *App) reloadConfig() ConfigReloadResult {
+	result := ConfigReloadResult{ReloadedAt: time.Now()}
+	err := app.applyConfig(&result)
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Config reload failed")
+		result.Error = err.Error()
+	}
+	app.writeReloadResult(&result)
+	return result
+}
+
+func (app *App) applyConfig(result *ConfigReloadResult) error {
+	app.logger.Info().Msgf("Reloading config from %s", app.configFile)
+	loaded, err := config.ReadFromFile(app.configFile)
+	if err != nil {
+		return err
+	}
+	level, err := parseLevel(loaded.LogLevel)
+	if err != nil {
+		return err
+	}
+	if err = validateConfig(loaded); err != nil {
+		return err
+	}
+	aofMode, err := parseAofMode(loaded.AofMode)
+	if err != nil {
+		return err
+	}
+
+	updated, applied, restartRequired := splitConfigChanges(app.localConfig, loaded)
+	result.Applied = applied
+	result.RestartRequired = restartRequired
+	for _, key := range restartRequired {
+		app.logger.Warn().Msgf("Config reload: %s changed, restart required to apply", key)
+	}
+	for _, key := range applied {
+		app.logger.Info().Msgf("Config reload: applying %s", key)
+	}
+
+	app.localConfig = updated
+	app.aofMode = aofMode
+	app.applyEffectiveConfig()
+	app.logLevel.setLevel(level)
+
+	reconnectNodes := changedAny(applied,
+		"valkey.auth_user", "valkey.auth_password", "valkey.use_tls", "valkey.tls_ca_path",
+		"valkey.dial_timeout", "valkey.write_timeout")
+	if reconnectNodes && app.shard != nil {
+		app.logger.Info().Msg("Config reload: reconnecting valkey nodes")
+		if err := app.shard.Reconnect(); err != nil {
+			app.logger.Warn().Err(err).Msg("Config reload: some nodes are not reachable after reconnect")
+		}
+	}
+	reconnectCache := changedAny(applied,
+		"sentinel_mode.cache_auth_user", "sentinel_mode.cache_auth_password", "sentinel_mode.use_tls",
+		"sentinel_mode.tls_ca_path", "valkey.dial_timeout", "valkey.write_timeout")
+	if reconnectCache && app.cache != nil {
+		app.logger.Info().Msg("Config reload: reconnecting senticache")
+		if err := app.cache.Reconnect(); err != nil {
+			app.logger.Warn().Err(err).Msg("Config reload: senticache is not reachable after reconnect")
+		}
+	}
+	return nil
+}
+
+func (app *App) writeReloadResult(result *ConfigReloadResult) {
+	if app.config().ReloadResultFile == "" {
+		return
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Failed to marshal config reload result")
+		return
+	}
+	err = os.WriteFile(app.config().ReloadResultFile, data, 0o640)
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Failed to write config reload result file")
+	}
+}
+
+func (app *App) configReloadHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	resultChan := make(chan ConfigReloadResult, 1)
+	select {
+	case app.reloadRequests <- resultChan:
+	case <-r.Context().Done():
+		return
+	}
+	var result ConfigReloadResult
+	select {
+	case result = <-resultChan:
+	case <-r.Context().Done():
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if result.Error != "" {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+	_ = json.MarshalWrite(w, result)
+}
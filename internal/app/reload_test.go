@@ -0,0 +1,54 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex/rdsync/internal/config"
+)
+
+// updateTestConfig publishes modified copy of current config snapshot as config reload does
+func updateTestConfig(app *App, update func(conf *config.Config)) {
+	conf := *app.config()
+	update(&conf)
+	app.configHolder.Set(&conf)
+}
+
+func TestSplitConfigChanges(t *testing.T) {
+	old, err := config.DefaultConfig()
+	require.NoError(t, err)
+	loaded := old
+	loaded.LogLevel = "Debug"
+	loaded.Valkey.FailoverTimeout = time.Minute
+	loaded.Valkey.AuthPassword = "new"
+	loaded.Valkey.Port = 6380
+	loaded.Zookeeper.Namespace = "/other"
+
+	updated, applied, restartRequired := splitConfigChanges(&old, &loaded)
+	require.Equal(t, []string{"loglevel", "valkey.auth_password", "valkey.failover_timeout"}, applied)
+	require.Equal(t, []string{"zookeeper.namespace", "valkey.port"}, restartRequired)
+	require.Equal(t, old.Valkey.Port, updated.Valkey.Port)
+	require.Equal(t, old.Zookeeper.Namespace, updated.Zookeeper.Namespace)
+	require.Equal(t, time.Minute, updated.Valkey.FailoverTimeout)
+	require.Equal(t, "new", updated.Valkey.AuthPassword)
+}
+
+func TestApplyEffectiveConfigInvalidOverride(t *testing.T) {
+	local, err := config.DefaultConfig()
+	require.NoError(t, err)
+	require.NoError(t, validateConfig(&local))
+	app := &App{
+		logger:       testLogger(),
+		localConfig:  &local,
+		configHolder: config.NewHolder(&local),
+		shardConfig:  map[string]string{"valkey.switch_back_window": "25:00-26:00"},
+	}
+	app.applyEffectiveConfig()
+	require.Equal(t, local.Valkey.SwitchBackWindow, app.config().Valkey.SwitchBackWindow)
+
+	app.shardConfig = map[string]string{"valkey.failover_timeout": "1m"}
+	app.applyEffectiveConfig()
+	require.Equal(t, time.Minute, app.config().Valkey.FailoverTimeout)
+}
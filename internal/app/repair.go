@@ -19,6 +19,9 @@ func (app *App) repairShard(shardState map[string]*HostState, activeNodes []stri
 		if !state.PingOk {
 			continue
 		}
+		if host != master && app.isHostInMaintenance(host) {
+			continue
+		}
 		if host == master {
 			app.repairMaster(masterNode, activeNodes, state)
 		} else {
@@ -43,18 +46,18 @@ func (app *App) repairShard(shardState map[string]*HostState, activeNodes []stri
 		}
 		rs := state.ReplicaState
 		if rs == nil || state.IsReplPaused || !replicates(masterState, rs, host, masterNode, true) {
-			if syncing < app.config.Valkey.MaxParallelSyncs {
+			if syncing < app.config().Valkey.MaxParallelSyncs {
 				app.repairReplica(node, masterState, state, master, host)
 				syncing++
 			} else {
-				app.logger.Error().Msgf("Leaving replica %s broken: currently syncing %d/%d", host, syncing, app.config.Valkey.MaxParallelSyncs)
+				app.logger.Error().Msgf("Leaving replica %s broken: currently syncing %d/%d", host, syncing, app.config().Valkey.MaxParallelSyncs)
 			}
 		}
 	}
 }
 
 func (app *App) repairMaster(node *valkey.Node, activeNodes []string, state *HostState) {
-	expectedNumReplicas := app.getNumReplicasToWrite(activeNodes)
+	expectedNumReplicas := app.getNumReplicasToWrite(activeNodes, node.FQDN())
 	actualNumReplicas, err := node.GetNumQuorumReplicas(app.ctx)
 	if err != nil {
 		app.logger.Error().Str("fqdn", node.FQDN()).Err(err).Msg("Unable to get actual num quorum replicas on master")
@@ -95,10 +98,10 @@ func (app *App) repairReplica(node *valkey.Node, masterState, state *HostState,
 		if app.replFailTime.IsZero() {
 			app.replFailTime = time.Now()
 		}
-		if time.Since(app.replFailTime) > app.config.Valkey.DestructiveReplicationRepairTimeout && app.config.Valkey.DestructiveReplicationRepairCommand != "" {
+		if time.Since(app.replFailTime) > app.config().Valkey.DestructiveReplicationRepairTimeout && app.config().Valkey.DestructiveReplicationRepairCommand != "" {
 			app.logger.Error().Msgf("Replication is broken for too long: %s. Using destructive repair: %s",
-				time.Since(app.replFailTime), app.config.Valkey.DestructiveReplicationRepairCommand)
-			split := strings.Fields(app.config.Valkey.DestructiveReplicationRepairCommand)
+				time.Since(app.replFailTime), app.config().Valkey.DestructiveReplicationRepairCommand)
+			split := strings.Fields(app.config().Valkey.DestructiveReplicationRepairCommand)
 			cmd := exec.CommandContext(app.ctx, split[0], split[1:]...)
 			err := cmd.Run()
 			if err != nil {
@@ -128,9 +131,9 @@ func (app *App) repairReplica(node *valkey.Node, masterState, state *HostState,
 					app.logger.Error().Err(err).Msgf("Unable to make %s replica of %s", node.FQDN(), master)
 					return
 				}
-				err = node.ClusterMeet(app.ctx, masterIP, app.config.Valkey.Port, app.config.Valkey.ClusterBusPort)
+				err = node.ClusterMeet(app.ctx, masterIP, app.config().Valkey.Port, app.config().Valkey.ClusterBusPort)
 				if err != nil {
-					app.logger.Error().Err(err).Msgf("Unable to make %s meet with master %s at %s:%d:%d", node.FQDN(), master, masterIP, app.config.Valkey.Port, app.config.Valkey.ClusterBusPort)
+					app.logger.Error().Err(err).Msgf("Unable to make %s meet with master %s at %s:%d:%d", node.FQDN(), master, masterIP, app.config().Valkey.Port, app.config().Valkey.ClusterBusPort)
 					return
 				}
 			}
@@ -179,7 +182,7 @@ func (app *App) reservedConnectionsWatchdog(info map[string]string) error {
 		return fmt.Errorf("unable to parse connected_clients from info: %w", err)
 	}
 	freeConns := parsedMaxClients - parsedClusterConns - parsedConnectedClients
-	if freeConns < int64(app.config.Valkey.ReservedConnections) {
+	if freeConns < int64(app.config().Valkey.ReservedConnections) {
 		app.logger.Warn().Msgf("Local node has %d free connections left. Killing all client connections.", freeConns)
 		node := app.shard.Local()
 		err = node.DisconnectClients(app.ctx, "normal")
@@ -201,7 +204,7 @@ func (app *App) repairLocalNode(master string) bool {
 			app.nodeFailTime[local.FQDN()] = time.Now()
 		}
 		failedTime := time.Since(app.nodeFailTime[local.FQDN()])
-		if failedTime > app.config.Valkey.BusyTimeout && strings.HasPrefix(err.Error(), "BUSY ") {
+		if failedTime > app.config().Valkey.BusyTimeout && strings.HasPrefix(err.Error(), "BUSY ") {
 			err = local.ScriptKill(app.ctx)
 			if err != nil {
 				app.logger.Error().Err(err).Msg("Local node is busy running a script. But SCRIPT KILL failed")
@@ -209,7 +212,7 @@ func (app *App) repairLocalNode(master string) bool {
 		}
 		if strings.HasPrefix(err.Error(), "LOADING ") {
 			app.nodeFailTime[local.FQDN()] = time.Now()
-		} else if failedTime > app.config.Valkey.RestartTimeout {
+		} else if failedTime > app.config().Valkey.RestartTimeout {
 			app.nodeFailTime[local.FQDN()] = time.Now()
 			err = local.Restart(app.ctx)
 			if err != nil {
@@ -281,7 +284,7 @@ func (app *App) repairLocalNode(master string) bool {
 	} else if master == local.FQDN() {
 		if !state.IsMaster {
 			app.logger.Error().Msg("Local node is alone in shard and is replica. Promoting")
-			if err := app.promote(master, master, shardState, time.Now().Add(app.config.Valkey.WaitPromoteForceTimeout)); err != nil {
+			if err := app.promote(master, master, shardState, time.Now().Add(app.config().Valkey.WaitPromoteForceTimeout)); err != nil {
 				app.logger.Error().Err(err).Msg("Unable to promote lone node in shard")
 				return false
 			}
@@ -305,14 +308,14 @@ func (app *App) repairLocalNode(master string) bool {
 		}
 
 		if replPaused || !replicates(shardState[master], state.ReplicaState, local.FQDN(), nil, true) {
-			if syncing < app.config.Valkey.MaxParallelSyncs {
+			if syncing < app.config().Valkey.MaxParallelSyncs {
 				app.logger.Info().Msg("Repairing local replica as it is offline and not replicates from primary")
 				app.repairReplica(local, shardState[master], state, master, local.FQDN())
 			} else {
-				app.logger.Error().Msgf("Leaving local offline replica broken: currently syncing %d/%d", syncing, app.config.Valkey.MaxParallelSyncs)
+				app.logger.Error().Msgf("Leaving local offline replica broken: currently syncing %d/%d", syncing, app.config().Valkey.MaxParallelSyncs)
 			}
 		}
-		if shardState[master].PingOk && shardState[master].PingStable && time.Since(shardState[master].CheckAt) < 3*app.config.HealthCheckInterval {
+		if shardState[master].PingOk && shardState[master].PingStable && time.Since(shardState[master].CheckAt) < 3*app.config().HealthCheckInterval {
 			app.logger.Error().Msg("Not making local node online: considered stale")
 			return false
 		}
@@ -329,7 +332,7 @@ func (app *App) repairLocalNode(master string) bool {
 			app.logger.Error().Err(err).Msg("Unable to get active nodes before setting local master online")
 			return false
 		}
-		expectedNumReplicas := app.getNumReplicasToWrite(activeNodes)
+		expectedNumReplicas := app.getNumReplicasToWrite(activeNodes, master)
 		actualNumReplicas, err := local.GetNumQuorumReplicas(app.ctx)
 		if err != nil {
 			app.logger.Error().Err(err).Msg("Unable to get num quorum replicas before setting local master online")
@@ -0,0 +1,285 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/yandex/rdsync/internal/dcs"
+)
+
+// maxScheduledIDAttempts limits id suffixes tried for operations added within the same second
+const maxScheduledIDAttempts = 100
+
+func validateScheduledOperation(op *ScheduledOperation) error {
+	switch op.Operation {
+	case ScheduledSwitchover, ScheduledMaintenanceOn, ScheduledMaintenanceOff:
+	default:
+		return fmt.Errorf("unknown scheduled operation %s, supported: %s, %s, %s", op.Operation,
+			ScheduledSwitchover, ScheduledMaintenanceOn, ScheduledMaintenanceOff)
+	}
+	if op.At.IsZero() == (op.Window == "") {
+		return fmt.Errorf("exactly one of time or window should be set")
+	}
+	if _, err := parseTimeWindow(op.Window); err != nil {
+		return err
+	}
+	if op.Operation != ScheduledSwitchover && (op.From != "" || op.To != "") {
+		return fmt.Errorf("from and to are supported only for switchover")
+	}
+	return nil
+}
+
+// getScheduledOperations returns scheduled operations ordered by id
+func (app *App) getScheduledOperations() ([]*ScheduledOperation, error) {
+	ids, err := app.dcs.GetChildren(pathScheduledPrefix)
+	if errors.Is(err, dcs.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(ids)
+	var ops []*ScheduledOperation
+	for _, id := range ids {
+		var op ScheduledOperation
+		err = app.dcs.Get(dcs.JoinPath(pathScheduledPrefix, id), &op)
+		if errors.Is(err, dcs.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, &op)
+	}
+	return ops, nil
+}
+
+// shardHealthBlocker returns reason why shard is not healthy enough for scheduled operation
+func (app *App) shardHealthBlocker(shardState map[string]*HostState, activeNodes []string, master string) string {
+	if state, ok := shardState[master]; !ok || !state.PingOk {
+		return fmt.Sprintf("master %s is not alive", master)
+	}
+	permissibleReplicas := countAliveHAReplicasWithinNodes(app.votingNodes(activeNodes), shardState)
+	failoverQuorum := app.getFailoverQuorum(activeNodes, master)
+	if permissibleReplicas < failoverQuorum {
+		return fmt.Sprintf("no quorum, have %d replicas while %d is required", permissibleReplicas, failoverQuorum)
+	}
+	return ""
+}
+
+// executeScheduledOperation performs due operation and returns its result description
+func (app *App) executeScheduledOperation(op *ScheduledOperation, shardState map[string]*HostState, activeNodes []string,
+	master string, inMaintenance bool) (string, error) {
+	if op.Operation == ScheduledMaintenanceOff {
+		if !inMaintenance {
+			return "skipped: not in maintenance", nil
+		}
+		maintenance, err := app.GetMaintenance()
+		if err != nil {
+			return "", err
+		}
+		maintenance.ShouldLeave = true
+		return "maintenance disable scheduled", app.dcs.Set(pathMaintenance, maintenance)
+	}
+	if inMaintenance {
+		return "refused: shard is in maintenance", nil
+	}
+	if reason := app.shardHealthBlocker(shardState, activeNodes, master); reason != "" {
+		return "refused: " + reason, nil
+	}
+	switch op.Operation {
+	case ScheduledMaintenanceOn:
+		maintenance := &Maintenance{
+			InitiatedBy: app.config().Hostname,
+			InitiatedAt: time.Now(),
+			Reason:      fmt.Sprintf("scheduled %s: %s", op.ID, op.Reason),
+		}
+		window, err := parseTimeWindow(op.Window)
+		if err != nil {
+			return "", err
+		}
+		if window != nil {
+			// maintenance expires (and is left by manager) at the end of window
+			maintenance.ExpiresAt = window.endAfter(window.lastStart(maintenance.InitiatedAt))
+		}
+		err = app.dcs.Create(pathMaintenance, maintenance)
+		if errors.Is(err, dcs.ErrExists) {
+			return "skipped: maintenance already enabled", nil
+		}
+		return "maintenance enable scheduled", err
+	case ScheduledSwitchover:
+		if op.From != "" && op.From != master {
+			return fmt.Sprintf("skipped: master is %s, not %s", master, op.From), nil
+		}
+		if op.To == master {
+			return fmt.Sprintf("skipped: %s is already master", master), nil
+		}
+		if op.To != "" {
+			if reason := app.promotionBlocker(op.To); reason != "" {
+				return fmt.Sprintf("refused: %s is %s", op.To, reason), nil
+			}
+		}
+		switchover := Switchover{
+			From:        master,
+			To:          op.To,
+			Cause:       CauseWorker,
+			InitiatedBy: app.config().Hostname,
+			InitiatedAt: time.Now(),
+		}
+		err := app.dcs.Create(pathCurrentSwitch, switchover)
+		if errors.Is(err, dcs.ErrExists) {
+			return "refused: another switchover in progress", nil
+		}
+		return "switchover started", err
+	}
+	return "", fmt.Errorf("unknown scheduled operation %s", op.Operation)
+}
+
+// runScheduledOperations executes due scheduled operations and records results
+func (app *App) runScheduledOperations(shardState map[string]*HostState, activeNodes []string, master string, inMaintenance bool) {
+	ops, err := app.getScheduledOperations()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Failed to get scheduled operations from dcs")
+		return
+	}
+	now := time.Now()
+	for _, op := range ops {
+		if op.missed(now) {
+			app.logger.Warn().Msgf("Scheduled operation %s was not executed in time", op)
+			app.recordScheduledOperation(op, now, fmt.Sprintf("missed: not executed within %s after scheduled time", scheduledOperationGrace))
+			continue
+		}
+		if !op.due(now) {
+			continue
+		}
+		result, err := app.executeScheduledOperation(op, shardState, activeNodes, master, inMaintenance)
+		if err != nil {
+			app.logger.Error().Err(err).Msgf("Scheduled operation %s failed", op)
+			result = "failed: " + err.Error()
+		}
+		app.logger.Info().Msgf("Scheduled operation %s: %s", op.ID, result)
+		app.recordScheduledOperation(op, now, result)
+	}
+}
+
+func (app *App) recordScheduledOperation(op *ScheduledOperation, now time.Time, result string) {
+	op.LastRunAt = now
+	op.LastResult = result
+	op.Finished = op.Window == ""
+	err := app.dcs.Set(dcs.JoinPath(pathScheduledPrefix, op.ID), op)
+	if err != nil {
+		app.logger.Error().Err(err).Msgf("Failed to record result of scheduled operation %s", op.ID)
+	}
+}
+
+// CliScheduleList prints scheduled operations
+func (app *App) CliScheduleList() int {
+	err := app.connectDCS()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Unable to connect to dcs")
+		return 1
+	}
+	defer app.dcs.Close()
+	app.dcs.Initialize()
+
+	ops, err := app.getScheduledOperations()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Unable to get scheduled operations")
+		return 1
+	}
+	if len(ops) == 0 {
+		return 0
+	}
+	data, err := yaml.Marshal(ops)
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Unable to marshal scheduled operations")
+		return 1
+	}
+	fmt.Print(string(data))
+	return 0
+}
+
+// CliScheduleAdd stores operation to be executed by manager at given time or daily within window
+func (app *App) CliScheduleAdd(operation, at, window, from, to, reason string) int {
+	op := &ScheduledOperation{
+		Operation: operation,
+		Window:    window,
+		From:      from,
+		To:        to,
+		Reason:    reason,
+		CreatedBy: app.config().Hostname,
+		CreatedAt: time.Now(),
+	}
+	if at != "" {
+		ts, err := time.Parse(time.RFC3339, at)
+		if err != nil {
+			app.logger.Error().Err(err).Msg("Invalid time, expected RFC3339")
+			return 1
+		}
+		op.At = ts
+	}
+	if err := validateScheduledOperation(op); err != nil {
+		app.logger.Error().Err(err).Msg("Invalid scheduled operation")
+		return 1
+	}
+	if op.missed(op.CreatedAt) {
+		app.logger.Error().Msgf("Scheduled time %s is more than %s in the past", op.At, scheduledOperationGrace)
+		return 1
+	}
+
+	err := app.connectDCS()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Unable to connect to dcs")
+		return 1
+	}
+	defer app.dcs.Close()
+	app.dcs.Initialize()
+
+	// create fails on existing id, so operations added within the same second get numbered suffixes
+	baseID := fmt.Sprintf("%s-%s", op.CreatedAt.UTC().Format("20060102T150405"), operation)
+	op.ID = baseID
+	for i := 2; ; i++ {
+		err = app.dcs.Create(dcs.JoinPath(pathScheduledPrefix, op.ID), op)
+		if !errors.Is(err, dcs.ErrExists) || i > maxScheduledIDAttempts {
+			break
+		}
+		op.ID = fmt.Sprintf("%s-%d", baseID, i)
+	}
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Unable to store scheduled operation in dcs")
+		return 1
+	}
+	fmt.Printf("scheduled %s %s\n", op.ID, op)
+	return 0
+}
+
+// CliScheduleCancel removes scheduled operation
+func (app *App) CliScheduleCancel(id string) int {
+	err := app.connectDCS()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Unable to connect to dcs")
+		return 1
+	}
+	defer app.dcs.Close()
+	app.dcs.Initialize()
+
+	path := dcs.JoinPath(pathScheduledPrefix, id)
+	err = app.dcs.Get(path, new(ScheduledOperation))
+	if errors.Is(err, dcs.ErrNotFound) {
+		app.logger.Error().Msgf("Scheduled operation %s not found", id)
+		return 1
+	}
+	if err == nil {
+		err = app.dcs.Delete(path)
+	}
+	if err != nil {
+		app.logger.Error().Err(err).Msgf("Unable to cancel scheduled operation %s", id)
+		return 1
+	}
+	fmt.Printf("cancelled %s\n", id)
+	return 0
+}
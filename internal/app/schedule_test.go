@@ -0,0 +1,62 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduledOperationDue(t *testing.T) {
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+
+	once := &ScheduledOperation{Operation: ScheduledSwitchover, At: day.Add(2 * time.Hour)}
+	require.NoError(t, validateScheduledOperation(once))
+	require.False(t, once.due(day.Add(time.Hour)))
+	require.True(t, once.due(day.Add(2*time.Hour+time.Minute)))
+	once.Finished = true
+	require.False(t, once.due(day.Add(2*time.Hour+time.Minute)))
+
+	daily := &ScheduledOperation{Operation: ScheduledMaintenanceOn, Window: "23:00-01:00"}
+	require.NoError(t, validateScheduledOperation(daily))
+	require.False(t, daily.due(day.Add(12*time.Hour)))
+	require.True(t, daily.due(day.Add(23*time.Hour+30*time.Minute)))
+	daily.LastRunAt = day.Add(23*time.Hour + 30*time.Minute)
+	require.False(t, daily.due(day.Add(24*time.Hour+30*time.Minute)))
+	require.True(t, daily.due(day.Add(47*time.Hour)))
+
+	require.Error(t, validateScheduledOperation(&ScheduledOperation{Operation: ScheduledSwitchover}))
+	require.Error(t, validateScheduledOperation(&ScheduledOperation{Operation: "restart", At: day}))
+	require.Error(t, validateScheduledOperation(&ScheduledOperation{Operation: ScheduledMaintenanceOff, At: day, To: "valkey1"}))
+}
+
+func TestScheduledOperationMissed(t *testing.T) {
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+
+	once := &ScheduledOperation{Operation: ScheduledSwitchover, At: day.Add(2 * time.Hour)}
+	require.False(t, once.missed(day.Add(2*time.Hour)))
+	require.True(t, once.due(day.Add(2*time.Hour+scheduledOperationGrace-time.Second)))
+	require.False(t, once.due(day.Add(2*time.Hour+scheduledOperationGrace)))
+	require.True(t, once.missed(day.Add(2*time.Hour+scheduledOperationGrace)))
+	once.Finished = true
+	require.False(t, once.missed(day.Add(3*time.Hour)))
+
+	daily := &ScheduledOperation{Operation: ScheduledMaintenanceOn, Window: "23:00-01:00"}
+	require.False(t, daily.missed(day.Add(48*time.Hour)))
+}
+
+func TestTimeWindowEndAfter(t *testing.T) {
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+
+	overnight, err := parseTimeWindow("23:00-01:00")
+	require.NoError(t, err)
+	now := day.Add(23*time.Hour + 30*time.Minute)
+	require.Equal(t, day.Add(25*time.Hour), overnight.endAfter(overnight.lastStart(now)))
+	now = day.Add(24*time.Hour + 30*time.Minute)
+	require.Equal(t, day.Add(25*time.Hour), overnight.endAfter(overnight.lastStart(now)))
+
+	daytime, err := parseTimeWindow("10:00-12:00")
+	require.NoError(t, err)
+	now = day.Add(11 * time.Hour)
+	require.Equal(t, day.Add(12*time.Hour), daytime.endAfter(daytime.lastStart(now)))
+}
@@ -0,0 +1,132 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/yandex/rdsync/internal/valkey"
+)
+
+// failoverCandidate holds data available to candidate scorers
+type failoverCandidate struct {
+	state         *HostState
+	config        *valkey.NodeConfiguration
+	host          string
+	preferredZone string
+}
+
+// candidateScorer is a single failover candidate selection criterion.
+// Candidates are compared by scores of configured scorers in order.
+type candidateScorer interface {
+	// score returns criterion value (higher is better) or error if candidate should be excluded
+	score(candidate *failoverCandidate) (int64, error)
+}
+
+type scorerFunc func(candidate *failoverCandidate) (int64, error)
+
+func (f scorerFunc) score(candidate *failoverCandidate) (int64, error) {
+	return f(candidate)
+}
+
+// CandidateScore is a score breakdown of failover candidate
+type CandidateScore struct {
+	Scores   map[string]int64 `json:"scores"`
+	Host     string           `json:"host"`
+	Excluded string           `json:"excluded,omitempty"`
+}
+
+func boolScore(value bool) int64 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+func candidateScorers(maxMemoryUsage int64) map[string]candidateScorer {
+	return map[string]candidateScorer{
+		"priority": scorerFunc(func(c *failoverCandidate) (int64, error) {
+			return int64(c.config.Priority), nil
+		}),
+		"freshest": scorerFunc(func(c *failoverCandidate) (int64, error) {
+			return getOffset(c.state), nil
+		}),
+		"zone_affinity": scorerFunc(func(c *failoverCandidate) (int64, error) {
+			return boolScore(c.preferredZone != "" && c.config.Zone == c.preferredZone), nil
+		}),
+		"persistence": scorerFunc(func(c *failoverCandidate) (int64, error) {
+			return boolScore(c.state != nil && c.state.AofEnabled), nil
+		}),
+		"memory_pressure": scorerFunc(func(c *failoverCandidate) (int64, error) {
+			if c.state == nil || c.state.MaxMemory <= 0 {
+				return 0, nil
+			}
+			usage := c.state.UsedMemory * 100 / c.state.MaxMemory
+			if usage >= maxMemoryUsage {
+				return 0, fmt.Errorf("memory usage %d%% is over %d%%", usage, maxMemoryUsage)
+			}
+			return 0, nil
+		}),
+	}
+}
+
+// validateCandidateScoring checks that all configured scorers exist
+func validateCandidateScoring(names []string) error {
+	if len(names) == 0 {
+		return fmt.Errorf("candidate_scoring should contain at least one scorer")
+	}
+	scorers := candidateScorers(0)
+	for _, name := range names {
+		if _, ok := scorers[name]; !ok {
+			known := make([]string, 0, len(scorers))
+			for scorer := range scorers {
+				known = append(known, scorer)
+			}
+			slices.Sort(known)
+			return fmt.Errorf("unknown candidate scorer %s, supported: %s", name, strings.Join(known, ", "))
+		}
+	}
+	return nil
+}
+
+// scoreCandidates returns best candidate and score breakdown of all candidates
+func (app *App) scoreCandidates(candidates []*failoverCandidate) (string, []CandidateScore, error) {
+	names := app.config().Valkey.CandidateScoring
+	if err := validateCandidateScoring(names); err != nil {
+		return "", nil, err
+	}
+	scorers := candidateScorers(app.config().Valkey.CandidateMaxMemoryUsage)
+	slices.SortFunc(candidates, func(a, b *failoverCandidate) int {
+		return strings.Compare(a.host, b.host)
+	})
+	var best string
+	var bestValues []int64
+	scores := make([]CandidateScore, 0, len(candidates))
+	for _, candidate := range candidates {
+		result := CandidateScore{Host: candidate.host, Scores: make(map[string]int64, len(names))}
+		values := make([]int64, 0, len(names))
+		for _, name := range names {
+			value, err := scorers[name].score(candidate)
+			if err != nil {
+				result.Excluded = fmt.Sprintf("%s: %s", name, err.Error())
+				break
+			}
+			result.Scores[name] = value
+			values = append(values, value)
+		}
+		scores = append(scores, result)
+		if result.Excluded != "" {
+			app.logger.Info().Msgf("Candidate %s excluded by %s", candidate.host, result.Excluded)
+			continue
+		}
+		app.logger.Info().Msgf("Candidate %s scores: %v", candidate.host, result.Scores)
+		if best == "" || slices.Compare(values, bestValues) > 0 {
+			best = candidate.host
+			bestValues = values
+		}
+	}
+	if best == "" {
+		return "", scores, fmt.Errorf("all candidates are excluded by scoring")
+	}
+	return best, scores, nil
+}
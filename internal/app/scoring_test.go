@@ -0,0 +1,41 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex/rdsync/internal/config"
+	"github.com/yandex/rdsync/internal/valkey"
+)
+
+func TestCandidateScoring(t *testing.T) {
+	logger := zerolog.Nop()
+	app := &App{
+		configHolder: config.NewHolder(&config.Config{Valkey: config.ValkeyConfig{
+			CandidateScoring:        []string{"memory_pressure", "persistence", "priority"},
+			CandidateMaxMemoryUsage: 90,
+		}}),
+		logger: &logger,
+	}
+	candidates := []*failoverCandidate{
+		{host: "a", state: &HostState{AofEnabled: true, UsedMemory: 95, MaxMemory: 100}, config: &valkey.NodeConfiguration{Priority: 200}},
+		{host: "b", state: &HostState{AofEnabled: false}, config: &valkey.NodeConfiguration{Priority: 300}},
+		{host: "c", state: &HostState{AofEnabled: true, UsedMemory: 10, MaxMemory: 100}, config: &valkey.NodeConfiguration{Priority: 100}},
+	}
+	best, scores, err := app.scoreCandidates(candidates)
+	require.NoError(t, err)
+	require.Equal(t, "c", best)
+	require.Len(t, scores, 3)
+	require.NotEmpty(t, scores[0].Excluded)
+	require.Equal(t, int64(100), scores[2].Scores["priority"])
+
+	updateTestConfig(app, func(conf *config.Config) { conf.Valkey.CandidateScoring = []string{"priority"} })
+	best, _, err = app.scoreCandidates(candidates)
+	require.NoError(t, err)
+	require.Equal(t, "b", best)
+
+	require.Error(t, validateCandidateScoring([]string{"priority", "unknown"}))
+	require.Error(t, validateCandidateScoring(nil))
+}
@@ -0,0 +1,90 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"sort"
+
+	"github.com/yandex/rdsync/internal/config"
+	"github.com/yandex/rdsync/internal/dcs"
+)
+
+func (app *App) getShardConfig() (map[string]string, error) {
+	overrides := make(map[string]string)
+	err := app.dcs.Get(pathShardConfig, &overrides)
+	if err != nil && !errors.Is(err, dcs.ErrNotFound) {
+		return nil, err
+	}
+	return overrides, nil
+}
+
+// applyEffectiveConfig builds effective config from local one and shard-wide overrides
+func (app *App) applyEffectiveConfig() {
+	effective, err := app.localConfig.WithShardOverrides(app.shardConfig)
+	if err != nil {
+		app.logger.Warn().Err(err).Msg("Some shard config overrides were skipped")
+	}
+	if err = validateConfig(effective); err != nil {
+		app.logger.Warn().Err(err).Msg("Ignoring shard config overrides")
+		local := *app.localConfig
+		effective = &local
+	}
+	aofMode, err := parseAofMode(effective.AofMode)
+	if err != nil {
+		// local config is validated on start and reload
+		aofMode = app.aofMode
+	}
+	for _, key := range config.Diff(app.config(), effective) {
+		app.logger.Info().Msgf("Effective config: %s changed", key)
+	}
+	// effective config is published as a new snapshot to goroutines, shard and nodes
+	app.configHolder.Set(effective)
+	app.aofMode = aofMode
+}
+
+func (app *App) refreshShardConfig() {
+	overrides, err := app.getShardConfig()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Failed to get shard config from dcs")
+		return
+	}
+	if maps.Equal(overrides, app.shardConfig) {
+		return
+	}
+	app.shardConfig = overrides
+	app.applyEffectiveConfig()
+}
+
+// checkSettingsDivergence reports hosts running with different effective shard-wide settings
+// on divergence start, change and end only
+func (app *App) checkSettingsDivergence(shardStateDcs map[string]*HostState) {
+	divergence := make(map[string]string)
+	for _, key := range config.ShardKeys {
+		hostsByValue := make(map[string][]string)
+		for host, state := range shardStateDcs {
+			if state == nil || state.EffectiveSettings == nil {
+				continue
+			}
+			value := state.EffectiveSettings[key]
+			hostsByValue[value] = append(hostsByValue[value], host)
+		}
+		if len(hostsByValue) > 1 {
+			for _, hosts := range hostsByValue {
+				sort.Strings(hosts)
+			}
+			divergence[key] = fmt.Sprintf("%v", hostsByValue)
+		}
+	}
+	for key, hosts := range divergence {
+		if app.settingsDivergence[key] != hosts {
+			app.logger.Warn().Msgf("Hosts run with diverging effective %s: %s", key, hosts)
+		}
+	}
+	for key := range app.settingsDivergence {
+		if _, ok := divergence[key]; !ok {
+			app.logger.Info().Msgf("Hosts run with same effective %s again", key)
+		}
+	}
+	app.settingsDivergence = divergence
+}
@@ -0,0 +1,156 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/yandex/rdsync/internal/dcs"
+)
+
+const (
+	// splitBrainReport only records split-brain incident
+	splitBrainReport = "report"
+	// splitBrainFence fences writable masters not matching master in dcs
+	splitBrainFence = "fence"
+)
+
+func validateSplitBrainPolicy(policy string) error {
+	switch policy {
+	case splitBrainReport, splitBrainFence:
+		return nil
+	}
+	return fmt.Errorf("unknown split brain policy: %s", policy)
+}
+
+// getWritableMasters returns hosts accepting writes as master (with slots in cluster mode)
+func (app *App) getWritableMasters(shardState map[string]*HostState) []string {
+	var masters []string
+	for host, state := range shardState {
+		if !state.PingOk || !state.IsMaster || state.IsReadOnly || state.IsOffline {
+			continue
+		}
+		if app.mode == modeCluster {
+			node := app.shard.Get(host)
+			if node == nil {
+				continue
+			}
+			hasSlots, err := node.HasClusterSlots(app.ctx)
+			if err != nil {
+				app.logger.Error().Err(err).Msgf("Split brain detector: unable to check slots on %s", host)
+				continue
+			}
+			if !hasSlots {
+				continue
+			}
+		}
+		masters = append(masters, host)
+	}
+	slices.Sort(masters)
+	return masters
+}
+
+// splitBrainVictims returns masters to fence: all except dcs master if it is among them
+func splitBrainVictims(masters []string, dcsMaster string) []string {
+	if len(masters) < 2 || !slices.Contains(masters, dcsMaster) {
+		return nil
+	}
+	return filterOut(masters, []string{dcsMaster})
+}
+
+func (app *App) getSplitBrain() (*SplitBrainIncident, error) {
+	var incident SplitBrainIncident
+	err := app.dcs.Get(pathSplitBrain, &incident)
+	if errors.Is(err, dcs.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &incident, nil
+}
+
+// reportSplitBrain only logs writable masters, it is used on hosts not holding manager lock
+func (app *App) reportSplitBrain(shardState map[string]*HostState) {
+	masters := app.getWritableMasters(shardState)
+	if len(masters) > 1 {
+		app.logger.Error().Msgf("Split brain detected: writable masters %s", masters)
+	}
+}
+
+// detectSplitBrain checks that there is at most one writable master,
+// records incident in dcs and fences extra masters according to policy.
+// It must be called only by manager lock holder
+func (app *App) detectSplitBrain(shardState map[string]*HostState) {
+	err := app.dcs.Get(pathCurrentSwitch, new(Switchover))
+	if err == nil {
+		return
+	}
+	var dcsMaster string
+	err = app.dcs.Get(pathMasterNode, &dcsMaster)
+	if err != nil && !errors.Is(err, dcs.ErrNotFound) {
+		app.logger.Error().Err(err).Msg("Split brain detector: failed to get master from dcs")
+		return
+	}
+	incident, err := app.getSplitBrain()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Split brain detector: failed to get incident from dcs")
+		return
+	}
+	masters := app.getWritableMasters(shardState)
+	if len(masters) < 2 {
+		if incident != nil && !incident.Resolved() {
+			incident.ResolvedAt = time.Now()
+			app.logger.Info().Msgf("Split brain resolved: %s", incident)
+			app.timings.reportTiming("split_brain", incident.ResolvedAt.Sub(incident.DetectedAt))
+			if err = app.dcs.Set(pathSplitBrain, incident); err != nil {
+				app.logger.Error().Err(err).Msg("Split brain detector: failed to resolve incident in dcs")
+			}
+		}
+		return
+	}
+	created := false
+	if incident == nil || incident.Resolved() {
+		incident = &SplitBrainIncident{
+			DetectedAt: time.Now(),
+			DetectedBy: app.config().Hostname,
+			DcsMaster:  dcsMaster,
+			Masters:    masters,
+		}
+		created = true
+		app.logger.Error().Msgf("Split brain detected: writable masters %s, dcs master %s", masters, dcsMaster)
+		app.timings.reportEvent("split_brain_detected", masters)
+	}
+	fencedBefore := len(incident.FencedHosts)
+	if app.config().Valkey.SplitBrainPolicy == splitBrainFence {
+		for _, host := range splitBrainVictims(masters, dcsMaster) {
+			if slices.Contains(incident.FencedHosts, host) {
+				continue
+			}
+			fence := &PoisonPill{
+				InitiatedAt: time.Now(),
+				InitiatedBy: app.config().Hostname,
+				TargetHost:  host,
+				Cause:       fmt.Sprintf("split brain with dcs master %s", dcsMaster),
+			}
+			if app.config().Valkey.SplitBrainFenceTTL > 0 {
+				fence.ExpiresAt = fence.InitiatedAt.Add(app.config().Valkey.SplitBrainFenceTTL)
+			}
+			err = app.dcs.Set(dcs.JoinPath(pathFencesPrefix, host), fence)
+			if err != nil {
+				app.logger.Error().Err(err).Msgf("Split brain detector: failed to fence %s", host)
+				continue
+			}
+			app.logger.Warn().Msgf("Split brain detector: fenced %s", host)
+			incident.FencedHosts = append(incident.FencedHosts, host)
+		}
+	}
+	// incident is rewritten only on creation and fencing of new hosts
+	if !created && len(incident.FencedHosts) == fencedBefore {
+		return
+	}
+	if err = app.dcs.Set(pathSplitBrain, incident); err != nil {
+		app.logger.Error().Err(err).Msg("Split brain detector: failed to record incident in dcs")
+	}
+}
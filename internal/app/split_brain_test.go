@@ -0,0 +1,76 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex/rdsync/internal/config"
+)
+
+func TestSplitBrainVictims(t *testing.T) {
+	app := &App{mode: modeSentinel}
+	shardState := map[string]*HostState{
+		"valkey1": {PingOk: true, IsMaster: true},
+		"valkey2": {PingOk: true, IsMaster: true},
+		"valkey3": {PingOk: true, IsMaster: true, IsReadOnly: true},
+		"valkey4": {PingOk: true},
+	}
+	masters := app.getWritableMasters(shardState)
+	require.Equal(t, []string{"valkey1", "valkey2"}, masters)
+	require.Equal(t, []string{"valkey2"}, splitBrainVictims(masters, "valkey1"))
+	// dcs master is not writable, it is unclear which one to keep
+	require.Empty(t, splitBrainVictims(masters, "valkey3"))
+	require.Empty(t, splitBrainVictims([]string{"valkey1"}, "valkey1"))
+}
+
+func TestDetectSplitBrain(t *testing.T) {
+	testDcs := newTestDCS()
+	conf, err := config.DefaultConfig()
+	require.NoError(t, err)
+	conf.Hostname = "valkey1"
+	conf.Valkey.SplitBrainPolicy = splitBrainFence
+	app := &App{
+		mode:         modeSentinel,
+		logger:       testLogger(),
+		dcs:          testDcs,
+		configHolder: config.NewHolder(&conf),
+	}
+	require.NoError(t, app.dcs.Set(pathMasterNode, "valkey1"))
+	splitState := map[string]*HostState{
+		"valkey1": {PingOk: true, IsMaster: true},
+		"valkey2": {PingOk: true, IsMaster: true},
+		"valkey3": {PingOk: true},
+	}
+
+	// incident is recorded and extra master is fenced
+	app.detectSplitBrain(splitState)
+	incident, err := app.getSplitBrain()
+	require.NoError(t, err)
+	require.NotNil(t, incident)
+	require.False(t, incident.Resolved())
+	require.Equal(t, []string{"valkey1", "valkey2"}, incident.Masters)
+	require.Equal(t, []string{"valkey2"}, incident.FencedHosts)
+	fence, err := app.getFence("valkey2")
+	require.NoError(t, err)
+	require.NotNil(t, fence)
+
+	// nothing changed, so nothing is written again and host is not fenced twice
+	writes := testDcs.writes
+	app.detectSplitBrain(splitState)
+	require.Equal(t, writes, testDcs.writes)
+
+	// incident is resolved once there is single writable master
+	app.detectSplitBrain(map[string]*HostState{
+		"valkey1": {PingOk: true, IsMaster: true},
+		"valkey2": {PingOk: true, IsMaster: true, IsReadOnly: true},
+	})
+	incident, err = app.getSplitBrain()
+	require.NoError(t, err)
+	require.True(t, incident.Resolved())
+	require.Equal(t, []string{"valkey2"}, incident.FencedHosts)
+
+	writes = testDcs.writes
+	app.detectSplitBrain(map[string]*HostState{"valkey1": {PingOk: true, IsMaster: true}})
+	require.Equal(t, writes, testDcs.writes)
+}
@@ -19,11 +19,11 @@ func (app *App) getHostState(fqdn string) *HostState {
 	node := app.shard.Get(fqdn)
 	var state HostState
 	state.CheckAt = time.Now()
-	state.CheckBy = app.config.Hostname
-	if app.mode == modeSentinel && fqdn == app.config.Hostname {
+	state.CheckBy = app.config().Hostname
+	if app.mode == modeSentinel && fqdn == app.config().Hostname {
 		state.SentiCacheState = &SentiCacheState{
-			Name:  app.config.SentinelMode.Name,
-			RunID: app.config.SentinelMode.RunID,
+			Name:  app.config().SentinelMode.Name,
+			RunID: app.config().SentinelMode.RunID,
 		}
 	}
 	info, minReplicasToWrite, isReadOnly, isOffline, isReplPaused, err := node.GetState(app.ctx)
@@ -184,6 +184,10 @@ func (app *App) getHostState(fqdn string) *HostState {
 	state.IsReadOnly = isReadOnly
 	state.IsOffline = isOffline
 	state.IsReplPaused = isReplPaused
+	// optional fields used by candidate scoring
+	state.AofEnabled = info["aof_enabled"] == "1"
+	state.UsedMemory, _ = strconv.ParseInt(info["used_memory"], 10, 64)
+	state.MaxMemory, _ = strconv.ParseInt(info["maxmemory"], 10, 64)
 	err = node.RefreshAddrs()
 	if err != nil {
 		app.setStateError(&state, fqdn, err.Error())
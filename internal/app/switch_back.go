@@ -0,0 +1,103 @@
+package app
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/yandex/rdsync/internal/dcs"
+)
+
+// getPreferredMaster returns the only host with the highest priority which could be promoted
+func (app *App) getPreferredMaster(hosts []string) string {
+	preferred := ""
+	best := -1
+	for _, host := range hosts {
+		nc, ok := app.nodeConfigs[host]
+		if !ok || app.promotionBlocker(host) != "" {
+			continue
+		}
+		switch {
+		case nc.Priority > best:
+			preferred = host
+			best = nc.Priority
+		case nc.Priority == best:
+			preferred = ""
+		}
+	}
+	return preferred
+}
+
+// switchBackBlocker returns reason why switch-back to preferred master is not possible now
+func (app *App) switchBackBlocker(shardState map[string]*HostState, activeNodes []string, master, preferred string) string {
+	if preferred == "" || preferred == master {
+		return "no preferred master"
+	}
+	if nc, ok := app.nodeConfigs[master]; ok && nc.Priority >= app.nodeConfigs[preferred].Priority {
+		return "master priority is not lower than preferred one"
+	}
+	state, ok := shardState[preferred]
+	if !ok || !state.PingOk || !state.PingStable {
+		return "preferred master is not healthy"
+	}
+	if !slices.Contains(activeNodes, preferred) {
+		return "preferred master is not active"
+	}
+	if state.ReplicaState == nil || !state.ReplicaState.MasterLinkState || app.isReplicaStale(state.ReplicaState, false) {
+		return "preferred master is not caught up"
+	}
+	return ""
+}
+
+// checkSwitchBack schedules switchover to preferred master once it is stable for configured period
+func (app *App) checkSwitchBack(shardState map[string]*HostState, activeNodes []string, master string) {
+	if !app.config().Valkey.SwitchBack {
+		return
+	}
+	preferred := app.getPreferredMaster(app.shard.Hosts())
+	if reason := app.switchBackBlocker(shardState, activeNodes, master, preferred); reason != "" {
+		if app.switchBackHost != "" {
+			app.logger.Info().Msgf("Switch-back to %s postponed: %s", app.switchBackHost, reason)
+		}
+		app.switchBackHost = ""
+		app.switchBackSince = time.Time{}
+		return
+	}
+	if app.switchBackHost != preferred {
+		app.switchBackHost = preferred
+		app.switchBackSince = time.Now()
+	}
+	if time.Since(app.switchBackSince) < app.config().Valkey.SwitchBackStablePeriod {
+		return
+	}
+	window, err := parseTimeWindow(app.config().Valkey.SwitchBackWindow)
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Invalid switch-back window")
+		return
+	}
+	if !window.contains(time.Now()) {
+		return
+	}
+	if err := app.checkFailoverFreeze(CauseWorker); err != nil {
+		app.logger.Info().Err(err).Msgf("Switch-back to %s skipped", preferred)
+		return
+	}
+	switchover := Switchover{
+		From:        master,
+		To:          preferred,
+		Cause:       CauseWorker,
+		InitiatedBy: app.config().Hostname,
+		InitiatedAt: time.Now(),
+	}
+	err = app.dcs.Create(pathCurrentSwitch, switchover)
+	if errors.Is(err, dcs.ErrExists) {
+		return
+	}
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Unable to create switch-back switchover in dcs")
+		return
+	}
+	app.logger.Info().Msgf("Scheduled switch-back from %s to preferred master %s", master, preferred)
+	app.switchBackHost = ""
+	app.switchBackSince = time.Time{}
+}
@@ -0,0 +1,52 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex/rdsync/internal/config"
+	"github.com/yandex/rdsync/internal/valkey"
+)
+
+func TestSwitchBack(t *testing.T) {
+	app := &App{configHolder: config.NewHolder(&config.Config{Valkey: config.ValkeyConfig{StaleReplicaLagClose: time.Minute}})}
+	app.nodeConfigs = map[string]*valkey.NodeConfiguration{
+		"valkey1": {Priority: 200},
+		"valkey2": {Priority: 100},
+		"valkey3": {Priority: 100},
+	}
+	hosts := []string{"valkey1", "valkey2", "valkey3"}
+	require.Equal(t, "valkey1", app.getPreferredMaster(hosts))
+
+	replica := &HostState{PingOk: true, PingStable: true, ReplicaState: &ReplicaState{MasterLinkState: true}}
+	shardState := map[string]*HostState{"valkey1": replica, "valkey2": {PingOk: true, PingStable: true, IsMaster: true}}
+	require.Empty(t, app.switchBackBlocker(shardState, hosts, "valkey2", "valkey1"))
+	require.NotEmpty(t, app.switchBackBlocker(shardState, []string{"valkey2", "valkey3"}, "valkey2", "valkey1"))
+	require.NotEmpty(t, app.switchBackBlocker(shardState, hosts, "valkey1", "valkey1"))
+
+	app.nodeConfigs["valkey1"].NeverPromote = true
+	require.Empty(t, app.getPreferredMaster(hosts))
+}
+
+func TestTimeWindow(t *testing.T) {
+	window, err := parseTimeWindow("22:00-04:00")
+	require.NoError(t, err)
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+	require.True(t, window.contains(day.Add(23*time.Hour)))
+	require.True(t, window.contains(day.Add(3*time.Hour)))
+	require.False(t, window.contains(day.Add(12*time.Hour)))
+
+	window, err = parseTimeWindow("02:00-05:30")
+	require.NoError(t, err)
+	require.True(t, window.contains(day.Add(5*time.Hour)))
+	require.False(t, window.contains(day.Add(6*time.Hour)))
+
+	window, err = parseTimeWindow("")
+	require.NoError(t, err)
+	require.True(t, window.contains(day))
+
+	_, err = parseTimeWindow("25:00-01:00")
+	require.Error(t, err)
+}
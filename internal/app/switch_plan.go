@@ -0,0 +1,133 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/yandex/rdsync/internal/dcs"
+)
+
+// ReplicaPlan describes expected state of replica after switchover
+type ReplicaPlan struct {
+	Resync       string `yaml:"resync"`
+	CatchUpBytes int64  `yaml:"catch_up_bytes"`
+	Active       bool   `yaml:"active"`
+}
+
+// SwitchoverPlan describes expected switchover result
+type SwitchoverPlan struct {
+	Replicas        map[string]ReplicaPlan `yaml:"replicas"`
+	CurrentMaster   string                 `yaml:"current_master"`
+	NewMaster       string                 `yaml:"new_master"`
+	MostRecent      string                 `yaml:"most_recent"`
+	Approval        string                 `yaml:"approval"`
+	ActiveNodes     []string               `yaml:"active_nodes"`
+	CandidateScores []CandidateScore       `yaml:"candidate_scores,omitempty"`
+	IgnoredChecks   []string               `yaml:"ignored_checks,omitempty"`
+	NewMasterLag    int64                  `yaml:"new_master_catch_up_bytes"`
+}
+
+// planSwitchover evaluates switchover like manager does without performing any action
+func (app *App) planSwitchover(switchover *Switchover, shardState map[string]*HostState, activeNodes []string, master string) *SwitchoverPlan {
+	plan := &SwitchoverPlan{
+		CurrentMaster: master,
+		ActiveNodes:   activeNodes,
+		IgnoredChecks: switchover.IgnoredChecks,
+		Replicas:      make(map[string]ReplicaPlan),
+		Approval:      "ok",
+	}
+	if err := app.approveSwitchover(switchover, activeNodes, shardState, master); err != nil {
+		plan.Approval = err.Error()
+	}
+	plan.MostRecent = app.findMostRecentNode(shardState)
+	switch {
+	case switchover.To != "":
+		plan.NewMaster = switchover.To
+	case switchover.From != "":
+		newMaster, scores, err := app.getMostDesirableNode(shardState, switchover.From, app.hostZone(master))
+		plan.CandidateScores = scores
+		if err != nil {
+			plan.Approval = fmt.Sprintf("no desirable node: %s", err.Error())
+			return plan
+		}
+		plan.NewMaster = newMaster
+	default:
+		plan.NewMaster = plan.MostRecent
+	}
+	recentOffset := getOffset(shardState[plan.MostRecent])
+	newMasterState := shardState[plan.NewMaster]
+	plan.NewMasterLag = recentOffset - getOffset(newMasterState)
+	for host, state := range shardState {
+		if host == plan.NewMaster {
+			continue
+		}
+		replica := ReplicaPlan{
+			Active:       slices.Contains(activeNodes, host),
+			Resync:       "partial",
+			CatchUpBytes: max(recentOffset-getOffset(state), 0),
+		}
+		switch {
+		case !state.PingOk:
+			replica.Resync = "unknown: host is not alive"
+		case host == master:
+			// old master becomes replica of new one, its own offset is compared directly
+			if getOffset(state) > recentOffset || !isPartialSyncPossible(&HostState{
+				PingOk:        true,
+				ReplicaState:  &ReplicaState{ReplicationOffset: getOffset(state)},
+				ReplicationID: state.ReplicationID,
+			}, newMasterState) {
+				replica.Resync = "full"
+			}
+		case !isPartialSyncPossible(state, newMasterState):
+			replica.Resync = "full"
+		}
+		plan.Replicas[host] = replica
+	}
+	return plan
+}
+
+// printSwitchoverPlan prints expected switchover result.
+// Returns 0 if switchover would be performed, 1 if it would be refused and 2 if another switchover is in progress.
+func (app *App) printSwitchoverPlan(master, fromHost, toHost string, activeNodes, ignoredChecks []string) int {
+	shardState, err := app.getShardStateFromDB()
+	if err != nil {
+		app.logger.Error().Err(err).Msg("No actual shard state")
+		return 1
+	}
+	switchover := &Switchover{
+		From:          fromHost,
+		To:            toHost,
+		Cause:         CauseManual,
+		InitiatedBy:   app.config().Hostname,
+		IgnoredChecks: ignoredChecks,
+	}
+	if switchover.From == "" && switchover.To == "" {
+		switchover.From = master
+	}
+	plan := app.planSwitchover(switchover, shardState, activeNodes, master)
+	inProgress := false
+	err = app.dcs.Get(pathCurrentSwitch, new(Switchover))
+	if err == nil {
+		plan.Approval = "another switchover in progress"
+		inProgress = true
+	} else if !errors.Is(err, dcs.ErrNotFound) {
+		app.logger.Error().Err(err).Msg("Unable to get current switchover status")
+		return 1
+	}
+	data, err := yaml.Marshal(plan)
+	if err != nil {
+		app.logger.Error().Err(err).Msg("Unable to marshal switchover plan")
+		return 1
+	}
+	fmt.Print(string(data))
+	switch {
+	case inProgress:
+		return 2
+	case plan.Approval != "ok":
+		return 1
+	}
+	return 0
+}
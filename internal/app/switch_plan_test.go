@@ -0,0 +1,136 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex/rdsync/internal/config"
+	"github.com/yandex/rdsync/internal/valkey"
+)
+
+func TestPlanSwitchover(t *testing.T) {
+	shardState := map[string]*HostState{
+		"m": {PingOk: true, PingStable: true, IsMaster: true, MasterReplicationOffset: 5000, ReplicationID: "id",
+			ReplicationBacklogStart: 1, ReplicationBacklogSize: 10000},
+		"r1": {PingOk: true, PingStable: true, ReplicationID: "id", ReplicationBacklogStart: 1, ReplicationBacklogSize: 10000,
+			ReplicaState: &ReplicaState{ReplicationOffset: 4990}},
+		"r2": {PingOk: true, PingStable: true, ReplicationID: "id", ReplicationBacklogStart: 1, ReplicationBacklogSize: 10000,
+			ReplicaState: &ReplicaState{ReplicationOffset: 4000}},
+	}
+	active := []string{"m", "r1", "r2"}
+
+	testCases := []struct {
+		nodeConfigs   map[string]*valkey.NodeConfiguration
+		name          string
+		newMaster     string
+		approval      string
+		preconditions []string
+		switchover    Switchover
+		newMasterLag  int64
+	}{
+		{
+			name:         "to most recent replica",
+			switchover:   Switchover{From: "m", To: "r1", Cause: CauseManual},
+			newMaster:    "r1",
+			approval:     "ok",
+			newMasterLag: 10,
+		},
+		{
+			name:         "from master",
+			switchover:   Switchover{From: "m", Cause: CauseManual},
+			newMaster:    "r1",
+			approval:     "ok",
+			newMasterLag: 10,
+		},
+		{
+			name:         "to never promote replica",
+			switchover:   Switchover{From: "m", To: "r2", Cause: CauseManual},
+			nodeConfigs:  map[string]*valkey.NodeConfiguration{"r2": {NeverPromote: true}},
+			newMaster:    "r2",
+			approval:     "switchover target r2 is never-promote",
+			newMasterLag: 1000,
+		},
+		{
+			name:       "from master skipping never promote replica",
+			switchover: Switchover{From: "m", Cause: CauseManual},
+			nodeConfigs: map[string]*valkey.NodeConfiguration{
+				"r1": {NeverPromote: true},
+			},
+			newMaster:    "r2",
+			approval:     "ok",
+			newMasterLag: 1000,
+		},
+		{
+			name:          "lagging target",
+			switchover:    Switchover{From: "m", To: "r2", Cause: CauseManual},
+			preconditions: []string{preconditionLag},
+			newMaster:     "r2",
+			approval:      "switchover preconditions failed: lag",
+			newMasterLag:  1000,
+		},
+		{
+			name:          "lagging target with ignored lag check",
+			switchover:    Switchover{From: "m", To: "r2", Cause: CauseManual, IgnoredChecks: []string{preconditionLag}},
+			preconditions: []string{preconditionLag},
+			newMaster:     "r2",
+			approval:      "ok",
+			newMasterLag:  1000,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			conf, err := config.DefaultConfig()
+			require.NoError(t, err)
+			conf.Valkey.SwitchoverPreconditions = tc.preconditions
+			conf.Valkey.SwitchoverMaxTargetLag = 100
+			app := &App{
+				logger:       testLogger(),
+				dcs:          newTestDCS(),
+				configHolder: config.NewHolder(&conf),
+				nodeConfigs:  tc.nodeConfigs,
+			}
+			app.shard = valkey.NewShard(app.configHolder, app.logger, app.dcs)
+			plan := app.planSwitchover(&tc.switchover, shardState, active, "m")
+			require.Equal(t, tc.newMaster, plan.NewMaster)
+			require.Equal(t, "m", plan.MostRecent)
+			require.Contains(t, plan.Approval, tc.approval)
+			require.Equal(t, tc.newMasterLag, plan.NewMasterLag)
+			require.NotContains(t, plan.Replicas, tc.newMaster)
+			for host, replica := range plan.Replicas {
+				require.Equal(t, "partial", replica.Resync, host)
+			}
+		})
+	}
+}
+
+func TestPlanSwitchoverExpiredFreeze(t *testing.T) {
+	shardState := map[string]*HostState{
+		"m": {PingOk: true, PingStable: true, IsMaster: true, MasterReplicationOffset: 5000, ReplicationID: "id",
+			ReplicationBacklogStart: 1, ReplicationBacklogSize: 10000},
+		"r1": {PingOk: true, PingStable: true, ReplicationID: "id", ReplicationBacklogStart: 1, ReplicationBacklogSize: 10000,
+			ReplicaState: &ReplicaState{ReplicationOffset: 4990}},
+	}
+	conf, err := config.DefaultConfig()
+	require.NoError(t, err)
+	conf.Valkey.SwitchoverPreconditions = switchoverPreconditions
+	testDcs := newTestDCS()
+	app := &App{
+		logger:       testLogger(),
+		dcs:          testDcs,
+		configHolder: config.NewHolder(&conf),
+	}
+	app.shard = valkey.NewShard(app.configHolder, app.logger, app.dcs)
+	freeze := &FailoverFreeze{FreezeSwitchover: true, ExpiresAt: time.Now().Add(-time.Minute)}
+	require.NoError(t, app.dcs.Set(pathFailoverFreeze, freeze))
+	writes := testDcs.writes
+
+	plan := app.planSwitchover(&Switchover{From: "m", Cause: CauseManual}, shardState, []string{"m", "r1"}, "m")
+	require.Equal(t, "ok", plan.Approval)
+	require.Equal(t, "r1", plan.NewMaster)
+	require.Equal(t, writes, testDcs.writes, "plan should not modify dcs")
+	current, err := app.getFailoverFreeze()
+	require.NoError(t, err)
+	require.NotNil(t, current)
+}
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"slices"
@@ -44,22 +45,62 @@ func (app *App) getLastSwitchover() Switchover {
 	return lastSwitch
 }
 
-func (app *App) approveSwitchover(switchover *Switchover, activeNodes []string, shardState map[string]*HostState) error {
+func (app *App) approveSwitchover(switchover *Switchover, activeNodes []string, shardState map[string]*HostState, master string) error {
 	if switchover.RunCount > 0 {
 		return nil
 	}
-	permissibleReplicas := countAliveHAReplicasWithinNodes(activeNodes, shardState)
-	failoverQuorum := app.getFailoverQuorum(activeNodes)
+	if err := app.checkFailoverFreeze(switchover.Cause); err != nil {
+		return err
+	}
+	if switchover.To != "" {
+		if reason := app.promotionBlocker(switchover.To); reason != "" {
+			return fmt.Errorf("switchover target %s is %s", switchover.To, reason)
+		}
+	}
+	// failover replaces dead master, so switchover preconditions are not applicable
+	if switchover.Cause != CauseAuto {
+		if err := app.checkSwitchoverPreconditions(switchover, activeNodes, shardState, master); err != nil {
+			return err
+		}
+	}
+	permissibleReplicas := countAliveHAReplicasWithinNodes(app.votingNodes(activeNodes), shardState)
+	failoverQuorum := app.getFailoverQuorum(activeNodes, master)
 	if permissibleReplicas < failoverQuorum {
 		return fmt.Errorf("no quorum, have %d replicas while %d is required", permissibleReplicas, failoverQuorum)
 	}
 	return nil
 }
 
+// waitSwitchover waits for switchover to appear in last (or last rejected) switchover with successful result
+func (app *App) waitSwitchover(switchover *Switchover, waitTimeout time.Duration) error {
+	waitCtx, cancel := context.WithTimeout(app.ctx, waitTimeout)
+	defer cancel()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			lastSwitchover := app.getLastSwitchover()
+			if lastSwitchover.InitiatedBy != switchover.InitiatedBy || lastSwitchover.InitiatedAt.Unix() != switchover.InitiatedAt.Unix() {
+				continue
+			}
+			if lastSwitchover.Result == nil {
+				continue
+			}
+			if !lastSwitchover.Result.Ok {
+				return fmt.Errorf("switchover failed: %s", lastSwitchover.Result.Error)
+			}
+			return nil
+		case <-waitCtx.Done():
+			return fmt.Errorf("switchover did not finish until deadline")
+		}
+	}
+}
+
 func (app *App) startSwitchover(switchover *Switchover) error {
 	app.logger.Info().Msgf("Switchover: %s => %s starting", switchover.From, switchover.To)
 	switchover.StartedAt = time.Now()
-	switchover.StartedBy = app.config.Hostname
+	switchover.StartedBy = app.config().Hostname
 	return app.dcs.Set(pathCurrentSwitch, switchover)
 }
 
@@ -117,6 +158,13 @@ func (app *App) finishSwitchover(switchover *Switchover, switchErr error) error
 	dur := time.Since(switchover.StartedAt)
 	app.timings.reportTiming(eventName, dur)
 
+	if switchErr != nil && switchover.RunCount > 0 {
+		err := app.dcs.Set(pathLastFailedSwitch, switchover)
+		if err != nil {
+			return err
+		}
+	}
+
 	err := app.dcs.Delete(pathCurrentSwitch)
 	if err != nil {
 		return err
@@ -155,7 +203,7 @@ func (app *App) performSwitchover(shardState map[string]*HostState, activeNodes
 		}
 	}
 
-	failoverQuorum := app.getFailoverQuorum(activeNodes)
+	failoverQuorum := app.getFailoverQuorum(activeNodes, oldMaster)
 
 	if switchover.Cause == CauseAuto && switchover.From == oldMaster {
 		activeNodes = filterOut(activeNodes, []string{oldMaster})
@@ -204,13 +252,13 @@ func (app *App) performSwitchover(shardState map[string]*HostState, activeNodes
 			}
 		}
 		if needIssue {
-			err := app.issuePoisonPill(oldMaster)
+			err := app.issuePoisonPill(oldMaster, fmt.Sprintf("%s switchover from old master", switchover.Cause))
 			if err != nil {
 				return fmt.Errorf("unable to issue poison pill for old master %s: %s", oldMaster, err.Error())
 			}
 		}
-		if switchover.Cause != CauseAuto {
-			app.waitPoisonPill(app.config.Valkey.WaitPoisonPillTimeout)
+		if err := app.ensureOldMasterFenced(switchover, oldMaster); err != nil {
+			return err
 		}
 	}
 
@@ -223,7 +271,7 @@ func (app *App) performSwitchover(shardState map[string]*HostState, activeNodes
 			return err
 		}
 		rs := shardState[host].ReplicaState
-		if (rs == nil || !rs.MasterLinkState) && !app.config.Valkey.TurnBeforeSwitchover {
+		if (rs == nil || !rs.MasterLinkState) && !app.config().Valkey.TurnBeforeSwitchover {
 			app.logger.Info().Msgf("Switchover: skipping replication pause on %s", host)
 			return nil
 		}
@@ -266,7 +314,7 @@ func (app *App) performSwitchover(shardState map[string]*HostState, activeNodes
 		if switchover.To != "" {
 			newMaster = switchover.To
 		} else if switchover.From != "" {
-			newMaster, err = app.getMostDesirableNode(states, switchover.From)
+			newMaster, switchover.Progress.CandidateScores, err = app.getMostDesirableNode(states, switchover.From, app.hostZone(oldMaster))
 			if err != nil {
 				errsResume := runParallel(func(host string) error {
 					if !shardState[host].PingOk {
@@ -362,17 +410,17 @@ func (app *App) performSwitchover(shardState map[string]*HostState, activeNodes
 			}
 		}
 		if needIssue {
-			err := app.issuePoisonPill(oldMaster)
+			err := app.issuePoisonPill(oldMaster, fmt.Sprintf("%s switchover from old master", switchover.Cause))
 			if err != nil {
 				return fmt.Errorf("unable to issue poison pill for old master %s: %s", oldMaster, err.Error())
 			}
 		}
-		if switchover.Cause != CauseAuto {
-			app.waitPoisonPill(app.config.Valkey.WaitPoisonPillTimeout)
+		if err := app.ensureOldMasterFenced(switchover, oldMaster); err != nil {
+			return err
 		}
 
 		newMasterNode := app.shard.Get(newMaster)
-		if len(aliveActiveNodes) == 1 || app.config.Valkey.AllowDataLoss {
+		if len(aliveActiveNodes) == 1 || app.config().Valkey.AllowDataLoss {
 			err, errConf := newMasterNode.SetReadWrite(app.ctx)
 			if err != nil {
 				return fmt.Errorf("unable to set %s available for write before promote: %s", newMaster, err.Error())
@@ -388,7 +436,7 @@ func (app *App) performSwitchover(shardState map[string]*HostState, activeNodes
 				return fmt.Errorf("unable to rewrite config on %s before promote: %s", newMaster, errConf.Error())
 			}
 		} else {
-			expectedNumReplicas := app.getNumReplicasToWrite(aliveActiveNodes)
+			expectedNumReplicas := app.getNumReplicasToWrite(aliveActiveNodes, newMaster)
 			err, errConf := newMasterNode.SetNumQuorumReplicas(app.ctx, expectedNumReplicas)
 			if err != nil {
 				return fmt.Errorf("unable to set num quorum replicas to %d on %s before promote: %s", expectedNumReplicas, newMaster, err.Error())
@@ -398,7 +446,7 @@ func (app *App) performSwitchover(shardState map[string]*HostState, activeNodes
 			}
 		}
 
-		if app.config.Valkey.TurnBeforeSwitchover {
+		if app.config().Valkey.TurnBeforeSwitchover {
 			var psyncNodes []string
 			for _, host := range aliveActiveNodes {
 				if host == newMaster {
@@ -429,8 +477,8 @@ func (app *App) performSwitchover(shardState map[string]*HostState, activeNodes
 				app.logger.Warn().Err(err).Msg("Unable to psync some replicas before promote")
 			}
 		}
-		deadline := time.Now().Add(app.config.Valkey.WaitPromoteTimeout)
-		forceDeadline := time.Now().Add(app.config.Valkey.WaitPromoteForceTimeout)
+		deadline := time.Now().Add(app.config().Valkey.WaitPromoteTimeout)
+		forceDeadline := time.Now().Add(app.config().Valkey.WaitPromoteForceTimeout)
 		promoted := false
 		for time.Now().Before(deadline) {
 			err = app.promote(newMaster, oldMaster, shardState, forceDeadline)
@@ -489,7 +537,7 @@ func (app *App) performSwitchover(shardState map[string]*HostState, activeNodes
 		shardState, err = app.getShardStateFromDB()
 		if err == nil {
 			sentiCacheUpdateErrs := runParallel(func(host string) error {
-				sentiCacheNode, err := valkey.NewRemoteSentiCacheNode(app.config, host, app.logger)
+				sentiCacheNode, err := valkey.NewRemoteSentiCacheNode(app.configHolder, host, app.logger)
 				if err != nil {
 					return err
 				}
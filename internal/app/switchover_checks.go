@@ -0,0 +1,139 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/yandex/rdsync/internal/dcs"
+)
+
+const (
+	// preconditionLag requires switchover target to be close enough to most recent host
+	preconditionLag = "lag"
+	// preconditionSync requires no full sync to be in progress on any host
+	preconditionSync = "sync"
+	// preconditionPsync requires all active replicas to be able to psync from target
+	preconditionPsync = "psync"
+	// preconditionRecentFailure requires no started and failed switchover within cooldown
+	preconditionRecentFailure = "recent_failure"
+)
+
+var switchoverPreconditions = []string{preconditionLag, preconditionSync, preconditionPsync, preconditionRecentFailure}
+
+// validateSwitchoverPreconditions checks that all configured preconditions exist
+func validateSwitchoverPreconditions(names []string) error {
+	for _, name := range names {
+		if !slices.Contains(switchoverPreconditions, name) {
+			return fmt.Errorf("unknown switchover precondition: %s", name)
+		}
+	}
+	return nil
+}
+
+// switchoverTarget returns host expected to become master after switchover, it is selected like in performSwitchover
+func (app *App) switchoverTarget(switchover *Switchover, shardState map[string]*HostState, master string) (string, error) {
+	switch {
+	case switchover.To != "":
+		return switchover.To, nil
+	case switchover.From != "":
+		target, _, err := app.getMostDesirableNode(shardState, switchover.From, app.hostZone(master))
+		if err != nil {
+			return "", fmt.Errorf("no desirable node: %w", err)
+		}
+		return target, nil
+	}
+	return app.findMostRecentNode(shardState), nil
+}
+
+// switchoverPreconditionFailures returns failed precondition descriptions keyed by precondition name
+func switchoverPreconditionFailures(checks []string, maxLag int64, target, recent string, activeNodes []string,
+	shardState map[string]*HostState, lastSwitchover *Switchover, cooldown time.Duration) map[string]string {
+	failures := make(map[string]string)
+	for _, check := range checks {
+		switch check {
+		case preconditionLag:
+			lag := getOffset(shardState[recent]) - getOffset(shardState[target])
+			if lag > maxLag {
+				failures[check] = fmt.Sprintf("target %s is %d bytes behind (max %d)", target, lag, maxLag)
+			}
+		case preconditionSync:
+			var syncing []string
+			for host, state := range shardState {
+				if state.PingOk && state.ReplicaState != nil && state.ReplicaState.MasterSyncInProgress {
+					syncing = append(syncing, host)
+				}
+			}
+			if len(syncing) > 0 {
+				slices.Sort(syncing)
+				failures[check] = fmt.Sprintf("full sync in progress on %s", strings.Join(syncing, ", "))
+			}
+		case preconditionPsync:
+			var unable []string
+			for _, host := range activeNodes {
+				state, ok := shardState[host]
+				if host == target || !ok || !state.PingOk || state.IsMaster {
+					continue
+				}
+				if !isPartialSyncPossible(state, shardState[target]) {
+					unable = append(unable, host)
+				}
+			}
+			if len(unable) > 0 {
+				slices.Sort(unable)
+				failures[check] = fmt.Sprintf("%s could not psync from %s", strings.Join(unable, ", "), target)
+			}
+		case preconditionRecentFailure:
+			// rejected switchovers were never started, so only failed runs count
+			if lastSwitchover != nil && lastSwitchover.RunCount > 0 && lastSwitchover.Result != nil && !lastSwitchover.Result.Ok &&
+				time.Since(lastSwitchover.Result.FinishedAt) < cooldown {
+				failures[check] = fmt.Sprintf("switchover %s failed at %s", lastSwitchover,
+					lastSwitchover.Result.FinishedAt.Format(time.RFC3339))
+			}
+		}
+	}
+	return failures
+}
+
+// checkSwitchoverPreconditions verifies configured switchover preconditions not ignored by switchover
+func (app *App) checkSwitchoverPreconditions(switchover *Switchover, activeNodes []string, shardState map[string]*HostState, master string) error {
+	var checks []string
+	for _, check := range app.config().Valkey.SwitchoverPreconditions {
+		if !slices.Contains(switchover.IgnoredChecks, check) {
+			checks = append(checks, check)
+		}
+	}
+	if len(checks) == 0 {
+		return nil
+	}
+	var lastSwitchover *Switchover
+	if slices.Contains(checks, preconditionRecentFailure) {
+		var last Switchover
+		err := app.dcs.Get(pathLastFailedSwitch, &last)
+		if err != nil && !errors.Is(err, dcs.ErrNotFound) {
+			return err
+		}
+		if err == nil {
+			lastSwitchover = &last
+		}
+	}
+	target, err := app.switchoverTarget(switchover, shardState, master)
+	if err != nil {
+		return err
+	}
+	failures := switchoverPreconditionFailures(checks, app.config().Valkey.SwitchoverMaxTargetLag,
+		target, app.findMostRecentNode(shardState), activeNodes,
+		shardState, lastSwitchover, app.config().Valkey.SwitchoverFailedCooldown)
+	if len(failures) == 0 {
+		return nil
+	}
+	messages := make([]string, 0, len(failures))
+	for _, check := range checks {
+		if failure, ok := failures[check]; ok {
+			messages = append(messages, fmt.Sprintf("%s: %s", check, failure))
+		}
+	}
+	return fmt.Errorf("switchover preconditions failed: %s", strings.Join(messages, "; "))
+}
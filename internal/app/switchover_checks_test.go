@@ -0,0 +1,93 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex/rdsync/internal/config"
+	"github.com/yandex/rdsync/internal/valkey"
+)
+
+func TestSwitchoverPreconditionFailures(t *testing.T) {
+	shardState := map[string]*HostState{
+		"m": {PingOk: true, IsMaster: true, MasterReplicationOffset: 5000, ReplicationID: "id",
+			ReplicationBacklogStart: 1, ReplicationBacklogSize: 10000},
+		"r1": {PingOk: true, ReplicationID: "id", ReplicaState: &ReplicaState{ReplicationOffset: 4990}},
+		"r2": {PingOk: true, ReplicationID: "other", ReplicaState: &ReplicaState{ReplicationOffset: 100, MasterSyncInProgress: true}},
+	}
+	active := []string{"m", "r1", "r2"}
+	all := switchoverPreconditions
+
+	failures := switchoverPreconditionFailures(all, 1000, "r1", "m", active, shardState, nil, time.Hour)
+	require.NotContains(t, failures, preconditionLag)
+	require.Contains(t, failures, preconditionSync)
+	require.Contains(t, failures, preconditionPsync)
+	require.NotContains(t, failures, preconditionRecentFailure)
+
+	failures = switchoverPreconditionFailures(all, 1000, "r2", "m", active, shardState, nil, time.Hour)
+	require.Contains(t, failures, preconditionLag)
+
+	rejected := &Switchover{From: "m", Result: &SwitchoverResult{Ok: false, FinishedAt: time.Now().Add(-time.Minute)}}
+	failures = switchoverPreconditionFailures([]string{preconditionRecentFailure}, 0, "r1", "m", active, shardState, rejected, time.Hour)
+	require.Empty(t, failures)
+
+	failed := &Switchover{From: "m", RunCount: 1, Result: &SwitchoverResult{Ok: false, FinishedAt: time.Now().Add(-time.Minute)}}
+	failures = switchoverPreconditionFailures([]string{preconditionRecentFailure}, 0, "r1", "m", active, shardState, failed, time.Hour)
+	require.Contains(t, failures, preconditionRecentFailure)
+	failures = switchoverPreconditionFailures([]string{preconditionRecentFailure}, 0, "r1", "m", active, shardState, failed, time.Second)
+	require.Empty(t, failures)
+
+	require.NoError(t, validateSwitchoverPreconditions(all))
+	require.Error(t, validateSwitchoverPreconditions([]string{"lag", "unknown"}))
+}
+
+func TestCheckSwitchoverPreconditions(t *testing.T) {
+	shardState := map[string]*HostState{
+		"m": {PingOk: true, PingStable: true, IsMaster: true, MasterReplicationOffset: 5000, ReplicationID: "id",
+			ReplicationBacklogStart: 1, ReplicationBacklogSize: 10000},
+		"r1": {PingOk: true, PingStable: true, ReplicationID: "id", ReplicationBacklogStart: 1, ReplicationBacklogSize: 10000,
+			ReplicaState: &ReplicaState{ReplicationOffset: 4990}},
+		"r2": {PingOk: true, PingStable: true, ReplicationID: "id", ReplicationBacklogStart: 1, ReplicationBacklogSize: 10000,
+			ReplicaState: &ReplicaState{ReplicationOffset: 4000}},
+	}
+	active := []string{"m", "r1", "r2"}
+	conf, err := config.DefaultConfig()
+	require.NoError(t, err)
+	conf.Valkey.SwitchoverPreconditions = []string{preconditionLag, preconditionRecentFailure}
+	conf.Valkey.SwitchoverMaxTargetLag = 100
+	conf.Valkey.SwitchoverFailedCooldown = time.Hour
+	app := &App{
+		logger:       testLogger(),
+		dcs:          newTestDCS(),
+		configHolder: config.NewHolder(&conf),
+	}
+	app.shard = valkey.NewShard(app.configHolder, app.logger, app.dcs)
+
+	// target is selected like in switchover itself
+	target, err := app.switchoverTarget(&Switchover{From: "m"}, shardState, "m")
+	require.NoError(t, err)
+	require.Equal(t, "r1", target)
+	app.nodeConfigs = map[string]*valkey.NodeConfiguration{"r1": {NeverPromote: true}}
+	target, err = app.switchoverTarget(&Switchover{From: "m"}, shardState, "m")
+	require.NoError(t, err)
+	require.Equal(t, "r2", target)
+	require.Error(t, app.checkSwitchoverPreconditions(&Switchover{From: "m", Cause: CauseManual}, active, shardState, "m"))
+	app.nodeConfigs = nil
+
+	// failover is not blocked by preconditions
+	lagging := &Switchover{From: "m", To: "r2", Cause: CauseManual}
+	require.Error(t, app.approveSwitchover(lagging, active, shardState, "m"))
+	lagging.Cause = CauseAuto
+	require.NoError(t, app.approveSwitchover(lagging, active, shardState, "m"))
+
+	// rejection is not a failure
+	switchover := &Switchover{From: "m", To: "r1", Cause: CauseManual}
+	rejected := &Switchover{From: "m", Result: &SwitchoverResult{Ok: false, FinishedAt: time.Now()}}
+	require.NoError(t, app.dcs.Set(pathLastRejectedSwitch, rejected))
+	require.NoError(t, app.checkSwitchoverPreconditions(switchover, active, shardState, "m"))
+	failed := &Switchover{From: "m", RunCount: 1, Result: &SwitchoverResult{Ok: false, FinishedAt: time.Now()}}
+	require.NoError(t, app.dcs.Set(pathLastFailedSwitch, failed))
+	require.ErrorContains(t, app.checkSwitchoverPreconditions(switchover, active, shardState, "m"), preconditionRecentFailure)
+}
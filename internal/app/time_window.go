@@ -0,0 +1,72 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// timeWindow is a daily time interval in local time, e.g. 22:00-04:00
+type timeWindow struct {
+	start time.Duration
+	end   time.Duration
+}
+
+func parseClock(value string) (time.Duration, error) {
+	ts, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %s, expected HH:MM", value)
+	}
+	return time.Duration(ts.Hour())*time.Hour + time.Duration(ts.Minute())*time.Minute, nil
+}
+
+// parseTimeWindow parses HH:MM-HH:MM window, empty value means any time
+func parseTimeWindow(value string) (*timeWindow, error) {
+	if value == "" {
+		return nil, nil
+	}
+	startStr, endStr, ok := strings.Cut(value, "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid time window %s, expected HH:MM-HH:MM", value)
+	}
+	start, err := parseClock(startStr)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseClock(endStr)
+	if err != nil {
+		return nil, err
+	}
+	return &timeWindow{start: start, end: end}, nil
+}
+
+// lastStart returns the most recent window start not after ts
+func (w *timeWindow) lastStart(ts time.Time) time.Time {
+	start := time.Date(ts.Year(), ts.Month(), ts.Day(), 0, 0, 0, 0, ts.Location()).Add(w.start)
+	if start.After(ts) {
+		start = start.AddDate(0, 0, -1)
+	}
+	return start
+}
+
+// endAfter returns the end of window occurrence started at start
+func (w *timeWindow) endAfter(start time.Time) time.Time {
+	end := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location()).Add(w.end)
+	if !end.After(start) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return end
+}
+
+// contains checks if time of day of ts is within window, nil window contains any time
+func (w *timeWindow) contains(ts time.Time) bool {
+	if w == nil {
+		return true
+	}
+	offset := time.Duration(ts.Hour())*time.Hour + time.Duration(ts.Minute())*time.Minute +
+		time.Duration(ts.Second())*time.Second
+	if w.start <= w.end {
+		return offset >= w.start && offset < w.end
+	}
+	return offset >= w.start || offset < w.end
+}
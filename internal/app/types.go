@@ -3,6 +3,8 @@ package app
 import (
 	"fmt"
 	"time"
+
+	"github.com/yandex/rdsync/internal/config"
 )
 
 type appState int
@@ -13,6 +15,7 @@ const (
 	stateCandidate
 	stateLost
 	stateMaintenance
+	stateWitness
 )
 
 func (s appState) String() string {
@@ -27,6 +30,8 @@ func (s appState) String() string {
 		return "Lost"
 	case stateMaintenance:
 		return "Maintenance"
+	case stateWitness:
+		return "Witness"
 	}
 	return "Unknown"
 }
@@ -95,6 +100,42 @@ func parseAofMode(mode string) (aofMode, error) {
 	return modeUnspecified, fmt.Errorf("unknown aof mode: %s", mode)
 }
 
+// validateConfig checks config values which could not be validated on parsing.
+// It is used on start, on reload and for effective config with shard-wide overrides
+func validateConfig(conf *config.Config) error {
+	if _, err := parseMode(conf.Mode); err != nil {
+		return err
+	}
+	if _, err := parseAofMode(conf.AofMode); err != nil {
+		return err
+	}
+	if conf.TickInterval <= 0 {
+		return fmt.Errorf("tick_interval should be positive, got %s", conf.TickInterval)
+	}
+	if err := validateCandidateScoring(conf.Valkey.CandidateScoring); err != nil {
+		return err
+	}
+	if err := validateReplicationQuorumPolicy(conf.Valkey.ReplicationQuorumPolicy, conf.Valkey.ReplicationQuorumSize); err != nil {
+		return err
+	}
+	if err := validateFencingPolicy(conf.Valkey.FencingPolicy); err != nil {
+		return err
+	}
+	if conf.Valkey.FencingPolicy == fencingRequired && len(conf.Valkey.FencingCommands) == 0 {
+		return fmt.Errorf("fencing_policy %s requires fencing_commands", fencingRequired)
+	}
+	if err := validateSwitchoverPreconditions(conf.Valkey.SwitchoverPreconditions); err != nil {
+		return err
+	}
+	if err := validateSplitBrainPolicy(conf.Valkey.SplitBrainPolicy); err != nil {
+		return err
+	}
+	if _, err := parseTimeWindow(conf.Valkey.SwitchBackWindow); err != nil {
+		return err
+	}
+	return nil
+}
+
 const (
 	// manager's lock
 	pathManagerLock = "manager"
@@ -108,6 +149,9 @@ const (
 	// structure: pathHealthPrefix/hostname -> NodeState
 	pathHealthPrefix = "health"
 
+	// structure: pathObservationsPrefix/hostname -> Observation
+	pathObservationsPrefix = "observations"
+
 	// structure: single Switchover
 	pathCurrentSwitch = "current_switch"
 
@@ -117,6 +161,10 @@ const (
 	// structure: single Switchover
 	pathLastRejectedSwitch = "last_rejected_switch"
 
+	// last switchover which was started and failed
+	// structure: single Switchover
+	pathLastFailedSwitch = "last_failed_switch"
+
 	// structure: single Maintenance
 	pathMaintenance = "maintenance"
 
@@ -127,31 +175,59 @@ const (
 	// fence flag
 	// structure: single PoisonPill
 	pathPoisonPill = "poison_pill"
+
+	// manually fenced hosts
+	// structure: pathFencesPrefix/hostname -> PoisonPill
+	pathFencesPrefix = "fences"
+
+	// last detected split-brain (several writable masters)
+	// structure: single SplitBrainIncident
+	pathSplitBrain = "split_brain"
+
+	// operations scheduled for future time or recurring window
+	// structure: pathScheduledPrefix/id -> ScheduledOperation
+	pathScheduledPrefix = "scheduled"
+
+	// automatic failover freeze
+	// structure: single FailoverFreeze
+	pathFailoverFreeze = "failover_freeze"
+
+	// automatic failover history for rate limiting
+	// structure: single FailoverBudget
+	pathFailoverBudget = "failover_budget"
+
+	// shard-wide config overrides
+	// structure: config key (e.g. valkey.failover_timeout) -> value
+	pathShardConfig = "shard_config"
 )
 
 // HostState contains status check performed by some rdsync process
 type HostState struct {
-	CheckAt                 time.Time        `json:"check_at"`
-	ReplicaState            *ReplicaState    `json:"replica_state"`
-	SentiCacheState         *SentiCacheState `json:"senticache_state"`
-	ReplicationID           string           `json:"replication_id"`
-	IP                      string           `json:"ip"`
-	RunID                   string           `json:"runid"`
-	Error                   string           `json:"error"`
-	ReplicationID2          string           `json:"replication_id2"`
-	CheckBy                 string           `json:"check_by"`
-	ConnectedReplicas       []string         `json:"connected_replicas"`
-	ReplicationBacklogStart int64            `json:"replication_backlog_start"`
-	SecondReplicationOffset int64            `json:"second_replication_offset"`
-	MasterReplicationOffset int64            `json:"master_replication_offset"`
-	ReplicationBacklogSize  int64            `json:"replication_backlog_size"`
-	MinReplicasToWrite      int64            `json:"min_replicas_to_write"`
-	IsReplPaused            bool             `json:"is_repl_paused"`
-	IsReadOnly              bool             `json:"is_read_only"`
-	IsOffline               bool             `json:"is_offline"`
-	IsMaster                bool             `json:"is_master"`
-	PingStable              bool             `json:"ping_stable"`
-	PingOk                  bool             `json:"ping_ok"`
+	CheckAt                 time.Time         `json:"check_at"`
+	ReplicaState            *ReplicaState     `json:"replica_state"`
+	SentiCacheState         *SentiCacheState  `json:"senticache_state"`
+	EffectiveSettings       map[string]string `json:"effective_settings"`
+	ReplicationID           string            `json:"replication_id"`
+	IP                      string            `json:"ip"`
+	RunID                   string            `json:"runid"`
+	Error                   string            `json:"error"`
+	ReplicationID2          string            `json:"replication_id2"`
+	CheckBy                 string            `json:"check_by"`
+	ConnectedReplicas       []string          `json:"connected_replicas"`
+	ReplicationBacklogStart int64             `json:"replication_backlog_start"`
+	SecondReplicationOffset int64             `json:"second_replication_offset"`
+	MasterReplicationOffset int64             `json:"master_replication_offset"`
+	ReplicationBacklogSize  int64             `json:"replication_backlog_size"`
+	MinReplicasToWrite      int64             `json:"min_replicas_to_write"`
+	UsedMemory              int64             `json:"used_memory"`
+	MaxMemory               int64             `json:"max_memory"`
+	IsReplPaused            bool              `json:"is_repl_paused"`
+	AofEnabled              bool              `json:"aof_enabled"`
+	IsReadOnly              bool              `json:"is_read_only"`
+	IsOffline               bool              `json:"is_offline"`
+	IsMaster                bool              `json:"is_master"`
+	PingStable              bool              `json:"ping_stable"`
+	PingOk                  bool              `json:"ping_ok"`
 }
 
 func (hs *HostState) String() string {
@@ -213,16 +289,19 @@ const (
 
 // Switchover contains info about currently running or scheduled switchover/failover process
 type Switchover struct {
-	InitiatedAt time.Time           `json:"initiated_at"`
-	StartedAt   time.Time           `json:"started_at"`
-	Result      *SwitchoverResult   `json:"result"`
-	Progress    *SwitchoverProgress `json:"progress"`
-	From        string              `json:"from"`
-	To          string              `json:"to"`
-	Cause       string              `json:"cause"`
-	InitiatedBy string              `json:"initiated_by"`
-	StartedBy   string              `json:"started_by"`
-	RunCount    int                 `json:"run_count"`
+	InitiatedAt   time.Time           `json:"initiated_at"`
+	StartedAt     time.Time           `json:"started_at"`
+	Result        *SwitchoverResult   `json:"result"`
+	Progress      *SwitchoverProgress `json:"progress"`
+	From          string              `json:"from"`
+	To            string              `json:"to"`
+	Cause         string              `json:"cause"`
+	InitiatedBy   string              `json:"initiated_by"`
+	StartedBy     string              `json:"started_by"`
+	Verifications []CommandResult     `json:"verifications,omitempty"`
+	Fencing       []CommandResult     `json:"fencing,omitempty"`
+	IgnoredChecks []string            `json:"ignored_checks,omitempty"`
+	RunCount      int                 `json:"run_count"`
 }
 
 func (sw *Switchover) String() string {
@@ -258,20 +337,28 @@ type SwitchoverResult struct {
 
 // SwitchoverProgress contains intents and status of running switchover
 type SwitchoverProgress struct {
-	NewMaster  string `json:"new_master"`
-	MostRecent string `json:"most_recent"`
-	Version    int    `json:"version"`
-	Phase      int    `json:"phase"`
+	NewMaster       string           `json:"new_master"`
+	MostRecent      string           `json:"most_recent"`
+	CandidateScores []CandidateScore `json:"candidate_scores,omitempty"`
+	Version         int              `json:"version"`
+	Phase           int              `json:"phase"`
 }
 
 // Maintenance struct presence means that cluster under manual control
 type Maintenance struct {
 	InitiatedAt  time.Time `json:"initiated_at"`
+	ExpiresAt    time.Time `json:"expires_at"`
 	InitiatedBy  string    `json:"initiated_by"`
+	Reason       string    `json:"reason"`
 	RdSyncPaused bool      `json:"rdsync_paused"`
 	ShouldLeave  bool      `json:"should_leave"`
 }
 
+// Expired returns true if maintenance has expiry time and it has passed
+func (m *Maintenance) Expired() bool {
+	return !m.ExpiresAt.IsZero() && time.Now().After(m.ExpiresAt)
+}
+
 func (m *Maintenance) String() string {
 	ms := "entering"
 	if m.RdSyncPaused {
@@ -280,21 +367,200 @@ func (m *Maintenance) String() string {
 	if m.ShouldLeave {
 		ms = "leaving"
 	}
-	return fmt.Sprintf("<%s by %s at %s>", ms, m.InitiatedBy, m.InitiatedAt)
+	details := ""
+	if m.Reason != "" {
+		details += ": " + m.Reason
+	}
+	if !m.ExpiresAt.IsZero() {
+		details += fmt.Sprintf(", expires %s", m.ExpiresAt)
+	}
+	return fmt.Sprintf("<%s by %s at %s%s>", ms, m.InitiatedBy, m.InitiatedAt, details)
+}
+
+// FailoverFreeze struct presence forbids automatic failover while keeping repair and quorum management
+type FailoverFreeze struct {
+	InitiatedAt      time.Time `json:"initiated_at"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	InitiatedBy      string    `json:"initiated_by"`
+	Reason           string    `json:"reason"`
+	FreezeSwitchover bool      `json:"freeze_switchover"`
+}
+
+// Expired returns true if freeze has expiry time and it has passed
+func (f *FailoverFreeze) Expired() bool {
+	return !f.ExpiresAt.IsZero() && time.Now().After(f.ExpiresAt)
+}
+
+func (f *FailoverFreeze) String() string {
+	scope := "failover"
+	if f.FreezeSwitchover {
+		scope = "failover and switchover"
+	}
+	expires := "never"
+	if !f.ExpiresAt.IsZero() {
+		expires = f.ExpiresAt.String()
+	}
+	return fmt.Sprintf("<%s frozen by %s at %s: %s, expires %s>", scope, f.InitiatedBy, f.InitiatedAt, f.Reason, expires)
+}
+
+// FailoverBudget contains recent automatic failovers used to rate limit them
+type FailoverBudget struct {
+	ResetAt     time.Time   `json:"reset_at"`
+	ResetBy     string      `json:"reset_by"`
+	ResetReason string      `json:"reset_reason"`
+	Failovers   []time.Time `json:"failovers"`
+}
+
+// used returns failovers within window which were not reset
+func (b *FailoverBudget) used(now time.Time, window time.Duration) []time.Time {
+	var used []time.Time
+	for _, ts := range b.Failovers {
+		if ts.After(b.ResetAt) && now.Sub(ts) < window {
+			used = append(used, ts)
+		}
+	}
+	return used
+}
+
+// nextAllowed returns time of next allowed failover: budget limit within window
+// and, with backoff, cooldown doubled for each recent failover
+func (b *FailoverBudget) nextAllowed(now time.Time, limit int, window, cooldown time.Duration, backoff bool) time.Time {
+	used := b.used(now, window)
+	var next time.Time
+	if limit > 0 && len(used) >= limit {
+		next = used[len(used)-limit].Add(window)
+	}
+	if backoff && len(used) > 0 {
+		delay := min(cooldown<<(len(used)-1), window)
+		if delay < 0 {
+			delay = window
+		}
+		if after := used[len(used)-1].Add(delay); after.After(next) {
+			next = after
+		}
+	}
+	return next
+}
+
+// SplitBrainIncident describes several writable masters seen at the same time
+type SplitBrainIncident struct {
+	DetectedAt  time.Time `json:"detected_at"`
+	ResolvedAt  time.Time `json:"resolved_at"`
+	DetectedBy  string    `json:"detected_by"`
+	DcsMaster   string    `json:"dcs_master"`
+	Masters     []string  `json:"masters"`
+	FencedHosts []string  `json:"fenced_hosts"`
+}
+
+// Resolved returns true if there is only one writable master again
+func (sb *SplitBrainIncident) Resolved() bool {
+	return !sb.ResolvedAt.IsZero()
+}
+
+func (sb *SplitBrainIncident) String() string {
+	state := "active"
+	if sb.Resolved() {
+		state = fmt.Sprintf("resolved at %s", sb.ResolvedAt)
+	}
+	return fmt.Sprintf("<%s masters %s (dcs master %s) detected by %s at %s, fenced %s>", state, sb.Masters,
+		sb.DcsMaster, sb.DetectedBy, sb.DetectedAt, sb.FencedHosts)
+}
+
+const (
+	// ScheduledSwitchover moves master (like rdsync switch)
+	ScheduledSwitchover = "switchover"
+	// ScheduledMaintenanceOn enables maintenance (like rdsync maint on)
+	ScheduledMaintenanceOn = "maintenance_on"
+	// ScheduledMaintenanceOff disables maintenance (like rdsync maint off)
+	ScheduledMaintenanceOff = "maintenance_off"
+)
+
+// scheduledOperationGrace is how long one-shot operation stays due after its time,
+// it is marked as missed afterwards (e.g. if there was no manager at that time)
+const scheduledOperationGrace = 15 * time.Minute
+
+// ScheduledOperation is an operation executed by manager once at given time or daily within window
+type ScheduledOperation struct {
+	At         time.Time `json:"at" yaml:"at,omitempty"`
+	CreatedAt  time.Time `json:"created_at" yaml:"created_at"`
+	LastRunAt  time.Time `json:"last_run_at" yaml:"last_run_at,omitempty"`
+	ID         string    `json:"id" yaml:"id"`
+	Operation  string    `json:"operation" yaml:"operation"`
+	Window     string    `json:"window" yaml:"window,omitempty"`
+	From       string    `json:"from" yaml:"from,omitempty"`
+	To         string    `json:"to" yaml:"to,omitempty"`
+	Reason     string    `json:"reason" yaml:"reason,omitempty"`
+	CreatedBy  string    `json:"created_by" yaml:"created_by"`
+	LastResult string    `json:"last_result" yaml:"last_result,omitempty"`
+	Finished   bool      `json:"finished" yaml:"finished"`
+}
+
+// due checks if operation should be executed now
+func (op *ScheduledOperation) due(now time.Time) bool {
+	if op.Finished {
+		return false
+	}
+	if op.Window == "" {
+		return !op.At.IsZero() && !now.Before(op.At) && now.Before(op.At.Add(scheduledOperationGrace))
+	}
+	window, err := parseTimeWindow(op.Window)
+	if err != nil || !window.contains(now) {
+		return false
+	}
+	// run once per window occurrence
+	return op.LastRunAt.Before(window.lastStart(now))
+}
+
+// missed checks if one-shot operation was not executed within grace period after its time
+func (op *ScheduledOperation) missed(now time.Time) bool {
+	return !op.Finished && op.Window == "" && !op.At.IsZero() && !now.Before(op.At.Add(scheduledOperationGrace))
+}
+
+func (op *ScheduledOperation) String() string {
+	when := op.At.String()
+	if op.Window != "" {
+		when = "daily " + op.Window
+	}
+	desc := op.Operation
+	if op.From != "" || op.To != "" {
+		desc = fmt.Sprintf("%s %s=>%s", desc, op.From, op.To)
+	}
+	if op.LastRunAt.IsZero() {
+		return fmt.Sprintf("<%s at %s by %s: %s>", desc, when, op.CreatedBy, op.Reason)
+	}
+	return fmt.Sprintf("<%s at %s by %s: %s, last run at %s: %s>", desc, when, op.CreatedBy, op.Reason,
+		op.LastRunAt, op.LastResult)
+}
+
+// Observation contains reachability of other shard members as seen by observer host
+type Observation struct {
+	CheckAt   time.Time       `json:"check_at"`
+	Reachable map[string]bool `json:"reachable"`
+	Witness   bool            `json:"witness,omitempty"`
 }
 
 type PoisonPill struct {
 	InitiatedAt time.Time `json:"initiated_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
 	InitiatedBy string    `json:"initiated_by"`
 	TargetHost  string    `json:"target_host"`
 	Cause       string    `json:"cause"`
 	Applied     bool      `json:"applied"`
 }
 
+// Expired returns true if poison pill has expiry time and it has passed
+func (pp *PoisonPill) Expired() bool {
+	return !pp.ExpiresAt.IsZero() && time.Now().After(pp.ExpiresAt)
+}
+
 func (pp *PoisonPill) String() string {
 	ms := "entering"
 	if pp.Applied {
 		ms = "on"
 	}
-	return fmt.Sprintf("<%s by %s for %s at %s: %s>", ms, pp.InitiatedBy, pp.TargetHost, pp.InitiatedAt, pp.Cause)
+	if pp.ExpiresAt.IsZero() {
+		return fmt.Sprintf("<%s by %s for %s at %s: %s>", ms, pp.InitiatedBy, pp.TargetHost, pp.InitiatedAt, pp.Cause)
+	}
+	return fmt.Sprintf("<%s by %s for %s at %s: %s, expires %s>", ms, pp.InitiatedBy, pp.TargetHost, pp.InitiatedAt,
+		pp.Cause, pp.ExpiresAt)
 }
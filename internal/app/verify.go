@@ -0,0 +1,150 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// verifyDelayExitCode is exit code (EX_TEMPFAIL) of verification command
+// which delays failover without recording rejection
+const verifyDelayExitCode = 75
+
+const hookOutputLimit = 1024
+
+// CommandResult contains result of single hook command
+type CommandResult struct {
+	Command  string `json:"command"`
+	Output   string `json:"output,omitempty"`
+	Error    string `json:"error,omitempty"`
+	ExitCode int    `json:"exit_code"`
+}
+
+var errFailoverDelayed = errors.New("failover delayed by verification command")
+
+// runHookCommand runs command with extra environment and returns its exit code and output tail
+func (app *App) runHookCommand(command string, env []string, timeout time.Duration) CommandResult {
+	result := CommandResult{Command: command}
+	ctx, cancel := context.WithTimeout(app.ctx, timeout)
+	defer cancel()
+	split := strings.Fields(command)
+	if len(split) == 0 {
+		result.Error = "empty command"
+		result.ExitCode = -1
+		return result
+	}
+	cmd := exec.CommandContext(ctx, split[0], split[1:]...)
+	cmd.Env = append(os.Environ(), env...)
+	output, err := cmd.CombinedOutput()
+	if len(output) > hookOutputLimit {
+		output = output[len(output)-hookOutputLimit:]
+	}
+	result.Output = strings.TrimSpace(string(output))
+	if err != nil {
+		result.Error = err.Error()
+		result.ExitCode = -1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && ctx.Err() == nil {
+			result.ExitCode = exitErr.ExitCode()
+		}
+	}
+	return result
+}
+
+// failoverVerification is a run of verification commands for single master failure
+type failoverVerification struct {
+	failTime time.Time
+	done     chan struct{}
+	master   string
+	results  []CommandResult
+	recorded bool
+}
+
+// verifyFailover runs configured verification commands before failover.
+// Commands run in background once per master failure so manager tick is never blocked by them:
+// failover is delayed until they finish. Non-zero exit of any command vetoes failover
+// and is recorded as rejected switchover once, exit code 75 only delays failover until next run.
+func (app *App) verifyFailover(master string, candidates []string) error {
+	if len(app.config().Valkey.FailoverVerifyCommands) == 0 {
+		return nil
+	}
+	verification := app.failoverVerification
+	if verification == nil || verification.master != master || !verification.failTime.Equal(app.nodeFailTime[master]) {
+		app.failoverVerification = app.startFailoverVerification(master, candidates)
+		return fmt.Errorf("%w: verification started", errFailoverDelayed)
+	}
+	select {
+	case <-verification.done:
+	default:
+		return fmt.Errorf("%w: verification is running", errFailoverDelayed)
+	}
+	var failed *CommandResult
+	for i := range verification.results {
+		if verification.results[i].Error != "" {
+			failed = &verification.results[i]
+			break
+		}
+	}
+	if failed == nil {
+		return nil
+	}
+	if failed.ExitCode == verifyDelayExitCode {
+		// rerun commands on next check
+		app.failoverVerification = nil
+		return fmt.Errorf("%w: %s", errFailoverDelayed, failed.Command)
+	}
+	err := fmt.Errorf("failover vetoed by verification command %s: %s", failed.Command, failed.Error)
+	if !verification.recorded {
+		rejected := Switchover{
+			InitiatedAt:   time.Now(),
+			InitiatedBy:   app.config().Hostname,
+			From:          master,
+			Cause:         CauseAuto,
+			Verifications: verification.results,
+			Result: &SwitchoverResult{
+				FinishedAt: time.Now(),
+				Error:      err.Error(),
+			},
+		}
+		if setErr := app.dcs.Set(pathLastRejectedSwitch, &rejected); setErr != nil {
+			app.logger.Error().Err(setErr).Msg("Failed to record vetoed failover")
+		} else {
+			verification.recorded = true
+		}
+	}
+	return err
+}
+
+// startFailoverVerification runs verification commands in background.
+// Failover verify timeout limits total time of all commands.
+func (app *App) startFailoverVerification(master string, candidates []string) *failoverVerification {
+	verification := &failoverVerification{
+		master:   master,
+		failTime: app.nodeFailTime[master],
+		done:     make(chan struct{}),
+	}
+	conf := app.config()
+	env := []string{
+		"RDSYNC_MASTER=" + master,
+		"RDSYNC_CANDIDATES=" + strings.Join(candidates, ","),
+		"RDSYNC_CAUSE=" + CauseAuto,
+		"RDSYNC_MANAGER=" + conf.Hostname,
+	}
+	go func() {
+		defer close(verification.done)
+		deadline := time.Now().Add(conf.Valkey.FailoverVerifyTimeout)
+		for _, command := range conf.Valkey.FailoverVerifyCommands {
+			result := app.runHookCommand(command, env, time.Until(deadline))
+			app.logger.Info().Msgf("Failover verification %s exited with %d: %s", command, result.ExitCode, result.Output)
+			verification.results = append(verification.results, result)
+			if result.Error != "" {
+				break
+			}
+		}
+	}()
+	return verification
+}
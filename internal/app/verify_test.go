@@ -0,0 +1,54 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yandex/rdsync/internal/config"
+)
+
+func TestVerifyFailover(t *testing.T) {
+	logger := zerolog.Nop()
+	app := &App{
+		ctx:          context.Background(),
+		logger:       &logger,
+		configHolder: config.NewHolder(&config.Config{Valkey: config.ValkeyConfig{FailoverVerifyTimeout: 10 * time.Second}}),
+	}
+	require.NoError(t, app.verifyFailover("master", []string{"r1"}))
+
+	script := filepath.Join(t.TempDir(), "verify.sh")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\necho \"$RDSYNC_MASTER $RDSYNC_CANDIDATES\"\nexit $1\n"), 0o755))
+
+	result := app.runHookCommand(script+" 3", []string{"RDSYNC_MASTER=master", "RDSYNC_CANDIDATES=r1,r2"}, time.Second)
+	require.Equal(t, 3, result.ExitCode)
+	require.Equal(t, "master r1,r2", result.Output)
+
+	app.nodeFailTime = map[string]time.Time{"master": time.Now()}
+	updateTestConfig(app, func(conf *config.Config) {
+		conf.Valkey.FailoverVerifyCommands = []string{script + " 0", script + " 75"}
+	})
+	require.ErrorIs(t, verifyFailoverDone(t, app), errFailoverDelayed)
+	require.Nil(t, app.failoverVerification)
+
+	updateTestConfig(app, func(conf *config.Config) { conf.Valkey.FailoverVerifyCommands = []string{script + " 0"} })
+	require.NoError(t, verifyFailoverDone(t, app))
+	require.NoError(t, app.verifyFailover("master", []string{"r1"}))
+
+	// new failure of master starts new verification
+	app.nodeFailTime["master"] = time.Now().Add(time.Second)
+	require.ErrorIs(t, app.verifyFailover("master", []string{"r1"}), errFailoverDelayed)
+	<-app.failoverVerification.done
+}
+
+// verifyFailoverDone starts verification and returns its result after commands finish
+func verifyFailoverDone(t *testing.T, app *App) error {
+	require.ErrorIs(t, app.verifyFailover("master", []string{"r1"}), errFailoverDelayed)
+	<-app.failoverVerification.done
+	return app.verifyFailover("master", []string{"r1"})
+}
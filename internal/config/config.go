@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/heetch/confita"
+	"github.com/heetch/confita/backend"
 	"github.com/heetch/confita/backend/file"
 
 	"github.com/yandex/rdsync/internal/dcs"
@@ -16,11 +17,20 @@ import (
 // ValkeyConfig contains valkey connection info and params
 type ValkeyConfig struct {
 	AuthPassword                        string        `yaml:"auth_password"`
+	AuthPasswordFile                    string        `yaml:"auth_password_file"`
 	AofPath                             string        `yaml:"aof_path"`
 	RestartCommand                      string        `yaml:"restart_command"`
 	TLSCAPath                           string        `yaml:"tls_ca_path"`
 	AuthUser                            string        `yaml:"auth_user"`
 	DestructiveReplicationRepairCommand string        `yaml:"destructive_replication_repair_command"`
+	ReplicationQuorumPolicy             string        `yaml:"replication_quorum_policy"`
+	FencingPolicy                       string        `yaml:"fencing_policy"`
+	SplitBrainPolicy                    string        `yaml:"split_brain_policy"`
+	SwitchBackWindow                    string        `yaml:"switch_back_window"`
+	CandidateScoring                    []string      `yaml:"candidate_scoring"`
+	FailoverVerifyCommands              []string      `yaml:"failover_verify_commands"`
+	FencingCommands                     []string      `yaml:"fencing_commands"`
+	SwitchoverPreconditions             []string      `yaml:"switchover_preconditions"`
 	RestartTimeout                      time.Duration `yaml:"restart_timeout"`
 	WaitPoisonPillTimeout               time.Duration `yaml:"wait_poison_pill_timeout"`
 	DNSTTL                              time.Duration `yaml:"dns_ttl"`
@@ -35,30 +45,47 @@ type ValkeyConfig struct {
 	WriteTimeout                        time.Duration `yaml:"write_timeout"`
 	StaleReplicaLagClose                time.Duration `yaml:"stale_replica_lag_close"`
 	StaleReplicaLagOpen                 time.Duration `yaml:"stale_replica_lag_open"`
+	JoinMaxLag                          int64         `yaml:"join_max_lag"`
+	CandidateMaxMemoryUsage             int64         `yaml:"candidate_max_memory_usage"`
+	SwitchoverMaxTargetLag              int64         `yaml:"switchover_max_target_lag"`
 	DestructiveReplicationRepairTimeout time.Duration `yaml:"destructive_replication_repair_timeout"`
 	FailoverCooldown                    time.Duration `yaml:"failover_cooldown"`
 	SwitchoverTimeout                   time.Duration `yaml:"switchover_timeout"`
 	MaxParallelSyncs                    int           `yaml:"max_parallel_syncs"`
 	ClusterBusPort                      int           `yaml:"cluster_bus_port"`
 	ReservedConnections                 int           `yaml:"reserved_connections"`
+	ReplicationQuorumSize               int           `yaml:"replication_quorum_size"`
+	FailoverObserverQuorum              int           `yaml:"failover_observer_quorum"`
+	FailoverVerifyTimeout               time.Duration `yaml:"failover_verify_timeout"`
+	FailoverBudgetWindow                time.Duration `yaml:"failover_budget_window"`
+	FencingTimeout                      time.Duration `yaml:"fencing_timeout"`
+	SplitBrainFenceTTL                  time.Duration `yaml:"split_brain_fence_ttl"`
+	SwitchBackStablePeriod              time.Duration `yaml:"switch_back_stable_period"`
+	SwitchoverFailedCooldown            time.Duration `yaml:"switchover_failed_cooldown"`
+	FailoverBudget                      int           `yaml:"failover_budget"`
 	TurnBeforeSwitchover                bool          `yaml:"turn_before_switchover"`
+	FailoverZoneMajority                bool          `yaml:"failover_zone_majority"`
+	FailoverBackoff                     bool          `yaml:"failover_backoff"`
+	SwitchBack                          bool          `yaml:"switch_back"`
 	UseTLS                              bool          `yaml:"use_tls"`
 	AllowDataLoss                       bool          `yaml:"allow_data_loss"`
 }
 
 // SentinelModeConfig contains sentinel-mode specific configuration
 type SentinelModeConfig struct {
-	Name                string `yaml:"name"`
-	RunID               string `yaml:"run_id"`
-	ClusterName         string `yaml:"cluster_name"`
-	CacheAuthUser       string `yaml:"cache_auth_user"`
-	CacheAuthPassword   string `yaml:"cache_auth_password"`
-	CacheRestartCommand string `yaml:"cache_restart_command"`
-	CacheUpdateSecret   string `yaml:"cache_update_secret"`
-	TLSCAPath           string `yaml:"tls_ca_path"`
-	CachePort           int    `yaml:"cache_port"`
-	AnnounceHostname    bool   `yaml:"announce_hostname"`
-	UseTLS              bool   `yaml:"use_tls"`
+	Name                  string `yaml:"name"`
+	RunID                 string `yaml:"run_id"`
+	ClusterName           string `yaml:"cluster_name"`
+	CacheAuthUser         string `yaml:"cache_auth_user"`
+	CacheAuthPassword     string `yaml:"cache_auth_password"`
+	CacheAuthPasswordFile string `yaml:"cache_auth_password_file"`
+	CacheRestartCommand   string `yaml:"cache_restart_command"`
+	CacheUpdateSecret     string `yaml:"cache_update_secret"`
+	CacheUpdateSecretFile string `yaml:"cache_update_secret_file"`
+	TLSCAPath             string `yaml:"tls_ca_path"`
+	CachePort             int    `yaml:"cache_port"`
+	AnnounceHostname      bool   `yaml:"announce_hostname"`
+	UseTLS                bool   `yaml:"use_tls"`
 }
 
 // Config contains rdsync application configuration
@@ -71,7 +98,9 @@ type Config struct {
 	DaemonLockFile          string              `yaml:"daemon_lock_file"`
 	PprofAddr               string              `yaml:"pprof_addr"`
 	EventTimingLogFile      string              `yaml:"event_timing_log_file"`
+	ReloadResultFile        string              `yaml:"reload_result_file"`
 	Mode                    string              `yaml:"mode"`
+	PinnedLocalSettings     []string            `yaml:"pinned_local_settings"`
 	SentinelMode            SentinelModeConfig  `yaml:"sentinel_mode"`
 	Zookeeper               dcs.ZookeeperConfig `yaml:"zookeeper"`
 	Valkey                  ValkeyConfig        `yaml:"valkey"`
@@ -80,10 +109,12 @@ type Config struct {
 	HealthCheckInterval     time.Duration       `yaml:"healthcheck_interval"`
 	InfoFileHandlerInterval time.Duration       `yaml:"info_file_handler_interval"`
 	InactivationDelay       time.Duration       `yaml:"inactivation_delay"`
+	MaintenanceWarnAge      time.Duration       `yaml:"maintenance_warn_age"`
 	DcsWaitTimeout          time.Duration       `yaml:"dcs_wait_timeout"`
 	DcsReconnectTimeout     time.Duration       `yaml:"dcs_reconnect_timeout"`
 	TickInterval            time.Duration       `yaml:"tick_interval"`
 	PingStable              int                 `yaml:"ping_stable"`
+	Witness                 bool                `yaml:"witness"`
 }
 
 // DefaultValkeyConfig returns default configuration for valkey connection info and params
@@ -108,6 +139,19 @@ func DefaultValkeyConfig() ValkeyConfig {
 		WaitPoisonPillTimeout:               30 * time.Second,
 		StaleReplicaLagClose:                90 * time.Second,
 		StaleReplicaLagOpen:                 10 * time.Second,
+		JoinMaxLag:                          1024 * 1024,
+		CandidateMaxMemoryUsage:             90,
+		FailoverVerifyTimeout:               30 * time.Second,
+		FailoverBudgetWindow:                24 * time.Hour,
+		FencingPolicy:                       "best_effort",
+		FencingTimeout:                      time.Minute,
+		SplitBrainPolicy:                    "report",
+		SplitBrainFenceTTL:                  5 * time.Minute,
+		SwitchBackStablePeriod:              10 * time.Minute,
+		SwitchoverMaxTargetLag:              1024 * 1024,
+		SwitchoverFailedCooldown:            30 * time.Minute,
+		ReplicationQuorumPolicy:             "majority",
+		CandidateScoring:                    []string{"priority", "freshest", "zone_affinity"},
 		BusyTimeout:                         5 * time.Second,
 		DestructiveReplicationRepairTimeout: 30 * time.Minute,
 		SwitchoverTimeout:                   10 * time.Minute,
@@ -173,11 +217,13 @@ func DefaultConfig() (Config, error) {
 		DaemonLockFile:          "/var/run/rdsync/rdsync.lock",
 		MaintenanceFile:         "/var/run/rdsync/rdsync.maintenance",
 		EventTimingLogFile:      "",
+		ReloadResultFile:        "/var/run/rdsync/rdsync.reload",
 		LogBufferSize:           10000,
 		LogPollInterval:         50 * time.Millisecond,
 		PingStable:              3,
 		TickInterval:            5 * time.Second,
 		InactivationDelay:       30 * time.Second,
+		MaintenanceWarnAge:      24 * time.Hour,
 		HealthCheckInterval:     5 * time.Second,
 		InfoFileHandlerInterval: 30 * time.Second,
 		PprofAddr:               "",
@@ -190,13 +236,24 @@ func DefaultConfig() (Config, error) {
 	return config, nil
 }
 
-// ReadFromFile reads config from file (not set values are replaced by default ones)
+// ReadFromFile reads config from file (not set values are replaced by default ones).
+// Values are layered in the following order: defaults, config file, drop-in directory files
+// (in lexical order), RDSYNC_* environment variables and contents of *_file secret files.
 func ReadFromFile(configFile string) (*Config, error) {
 	conf, err := DefaultConfig()
 	if err != nil {
 		return nil, err
 	}
-	loader := confita.NewLoader(file.NewBackend(configFile))
+	dropIns, err := dropInFiles(DropInDir(configFile))
+	if err != nil {
+		return nil, err
+	}
+	backends := []backend.Backend{file.NewBackend(configFile)}
+	for _, dropIn := range dropIns {
+		backends = append(backends, file.NewBackend(dropIn))
+	}
+	backends = append(backends, &envBackend{prefix: EnvPrefix}, &secretFileBackend{})
+	loader := confita.NewLoader(backends...)
 	if err = loader.Load(context.Background(), &conf); err != nil {
 		err = fmt.Errorf("failed to load config from %s: %s", configFile, err.Error())
 		return nil, err
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const baseConfig = `
+valkey:
+  auth_password: base
+  failover_timeout: 10s
+zookeeper:
+  namespace: /test
+  hosts: [zoo1:2181]
+`
+
+func writeFile(t *testing.T, path, data string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+}
+
+func TestReadFromFileLayers(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "rdsync.yaml")
+	writeFile(t, configFile, baseConfig)
+	writeFile(t, filepath.Join(dir, "rdsync.d", "20-timeouts.yaml"), "valkey:\n  failover_timeout: 30s\n")
+	writeFile(t, filepath.Join(dir, "rdsync.d", "10-timeouts.yaml"), "valkey:\n  failover_timeout: 20s\n  port: 6380\n")
+	t.Setenv("RDSYNC_VALKEY_PORT", "6381")
+	t.Setenv("RDSYNC_ZOOKEEPER_HOSTS", "zoo1:2181, zoo2:2181")
+
+	conf, err := ReadFromFile(configFile)
+	require.NoError(t, err)
+	require.Equal(t, 30*time.Second, conf.Valkey.FailoverTimeout)
+	require.Equal(t, 6381, conf.Valkey.Port)
+	require.Equal(t, []string{"zoo1:2181", "zoo2:2181"}, conf.Zookeeper.Hosts)
+	require.Equal(t, "base", conf.Valkey.AuthPassword)
+	require.Equal(t, DefaultValkeyConfig().DialTimeout, conf.Valkey.DialTimeout)
+}
+
+func TestReadFromFileSecretFiles(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "rdsync.yaml")
+	writeFile(t, configFile, baseConfig)
+	writeFile(t, filepath.Join(dir, "valkey_password"), "fromfile\n")
+	writeFile(t, filepath.Join(dir, "zk_password"), "zkfromfile")
+	writeFile(t, filepath.Join(dir, "zk_username"), "zkuser\n")
+	t.Setenv("RDSYNC_VALKEY_AUTH_PASSWORD_FILE", filepath.Join(dir, "valkey_password"))
+	t.Setenv("RDSYNC_ZOOKEEPER_PASSWORD_FILE", filepath.Join(dir, "zk_password"))
+	t.Setenv("RDSYNC_ZOOKEEPER_USERNAME_FILE", filepath.Join(dir, "zk_username"))
+
+	conf, err := ReadFromFile(configFile)
+	require.NoError(t, err)
+	require.Equal(t, "fromfile", conf.Valkey.AuthPassword)
+	require.Equal(t, "zkfromfile", conf.Zookeeper.Password)
+	require.Equal(t, "zkuser", conf.Zookeeper.Username)
+
+	t.Setenv("RDSYNC_SENTINEL_MODE_CACHE_UPDATE_SECRET_FILE", filepath.Join(dir, "missing"))
+	_, err = ReadFromFile(configFile)
+	require.Error(t, err)
+}
+
+func TestReadFromFileBadEnv(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "rdsync.yaml")
+	writeFile(t, configFile, baseConfig)
+	t.Setenv("RDSYNC_VALKEY_DIAL_TIMEOUT", "soon")
+
+	_, err := ReadFromFile(configFile)
+	require.ErrorContains(t, err, "RDSYNC_VALKEY_DIAL_TIMEOUT")
+}
+
+func TestRedacted(t *testing.T) {
+	conf, err := DefaultConfig()
+	require.NoError(t, err)
+	conf.Valkey.AuthPassword = "secret"
+	conf.SentinelMode.CacheUpdateSecret = "secret"
+	conf.Zookeeper.Password = "secret"
+
+	redacted := conf.Redacted()
+	require.Equal(t, redactedValue, redacted.Valkey.AuthPassword)
+	require.Equal(t, redactedValue, redacted.SentinelMode.CacheUpdateSecret)
+	require.Equal(t, redactedValue, redacted.Zookeeper.Password)
+	require.Empty(t, redacted.SentinelMode.CacheAuthPassword)
+	require.Equal(t, "secret", conf.Valkey.AuthPassword)
+}
+
+func TestDiff(t *testing.T) {
+	old, err := DefaultConfig()
+	require.NoError(t, err)
+	updated := old
+	updated.LogLevel = "Debug"
+	updated.Valkey.FailoverTimeout = time.Minute
+	updated.Zookeeper.Hosts = []string{"zoo1:2181"}
+	updated.Zookeeper.RandomHostProvider.LookupTTL = time.Second
+
+	require.Equal(t, []string{
+		"loglevel",
+		"zookeeper.hosts",
+		"zookeeper.random_host_provider.lookup_ttl",
+		"valkey.failover_timeout",
+	}, Diff(&old, &updated))
+	require.Empty(t, Diff(&old, &old))
+}
+
+func TestWithShardOverrides(t *testing.T) {
+	conf, err := DefaultConfig()
+	require.NoError(t, err)
+	conf.PinnedLocalSettings = []string{"valkey.failover_cooldown"}
+
+	effective, err := conf.WithShardOverrides(map[string]string{
+		"valkey.failover_timeout":   "1m",
+		"valkey.failover_cooldown":  "1h",
+		"valkey.max_parallel_syncs": "3",
+		"aof_mode":                  "On",
+	})
+	require.NoError(t, err)
+	require.Equal(t, time.Minute, effective.Valkey.FailoverTimeout)
+	require.Equal(t, conf.Valkey.FailoverCooldown, effective.Valkey.FailoverCooldown)
+	require.Equal(t, 3, effective.Valkey.MaxParallelSyncs)
+	require.Equal(t, "On", effective.AofMode)
+	require.Equal(t, DefaultValkeyConfig().FailoverTimeout, conf.Valkey.FailoverTimeout)
+
+	effective, err = conf.WithShardOverrides(map[string]string{
+		"valkey.port":             "6380",
+		"valkey.failover_timeout": "soon",
+		"inactivation_delay":      "1m",
+	})
+	require.Error(t, err)
+	require.Equal(t, conf.Valkey.Port, effective.Valkey.Port)
+	require.Equal(t, conf.Valkey.FailoverTimeout, effective.Valkey.FailoverTimeout)
+	require.Equal(t, time.Minute, effective.InactivationDelay)
+
+	value, err := effective.GetValue("inactivation_delay")
+	require.NoError(t, err)
+	require.Equal(t, "1m0s", value)
+	settings, err := effective.ShardSettings()
+	require.NoError(t, err)
+	require.Len(t, settings, len(ShardKeys))
+}
+
+func TestShardKeys(t *testing.T) {
+	require.True(t, slices.IsSorted(ShardKeys), "shard keys should be sorted")
+	var conf Config
+	for _, key := range ShardKeys {
+		_, err := conf.GetValue(key)
+		require.NoError(t, err, key)
+	}
+}
@@ -0,0 +1,26 @@
+package config
+
+import "sync/atomic"
+
+// Holder publishes config snapshots to concurrently running goroutines.
+// Snapshots are never modified after Set, changes are published as a new snapshot.
+type Holder struct {
+	current atomic.Pointer[Config]
+}
+
+// NewHolder returns holder publishing initial config
+func NewHolder(conf *Config) *Holder {
+	holder := &Holder{}
+	holder.current.Store(conf)
+	return holder
+}
+
+// Get returns current config snapshot
+func (h *Holder) Get() *Config {
+	return h.current.Load()
+}
+
+// Set publishes new config snapshot
+func (h *Holder) Set(conf *Config) {
+	h.current.Store(conf)
+}
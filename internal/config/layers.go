@@ -0,0 +1,265 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/heetch/confita/backend"
+)
+
+const (
+	// EnvPrefix is a prefix of environment variables overriding config values.
+	// Variable name is built from yaml keys, e.g. RDSYNC_VALKEY_AUTH_PASSWORD
+	EnvPrefix = "RDSYNC"
+
+	secretFileSuffix = "_file"
+	redactedValue    = "<redacted>"
+)
+
+var durationType = reflect.TypeFor[time.Duration]()
+
+// DropInDir returns drop-in directory path for config file (/etc/rdsync.yaml -> /etc/rdsync.d)
+func DropInDir(configFile string) string {
+	return strings.TrimSuffix(configFile, filepath.Ext(configFile)) + ".d"
+}
+
+// dropInFiles returns yaml files from drop-in directory in lexical order
+func dropInFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read drop-in directory %s: %w", dir, err)
+	}
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		switch filepath.Ext(entry.Name()) {
+		case ".yaml", ".yml":
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func yamlKey(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	if name == "-" || !field.IsExported() {
+		return ""
+	}
+	return name
+}
+
+// envBackend overrides config values with RDSYNC_* environment variables
+type envBackend struct {
+	prefix string
+}
+
+func (b *envBackend) Name() string {
+	return "env"
+}
+
+func (b *envBackend) Get(_ context.Context, _ string) ([]byte, error) {
+	return nil, backend.ErrNotFound
+}
+
+func (b *envBackend) Unmarshal(_ context.Context, to any) error {
+	return loadEnv(reflect.ValueOf(to).Elem(), b.prefix)
+}
+
+func loadEnv(value reflect.Value, prefix string) error {
+	valueType := value.Type()
+	for i := range valueType.NumField() {
+		key := yamlKey(valueType.Field(i))
+		if key == "" {
+			continue
+		}
+		name := prefix + "_" + strings.ToUpper(key)
+		field := value.Field(i)
+		if field.Kind() == reflect.Struct {
+			if err := loadEnv(field, name); err != nil {
+				return err
+			}
+			continue
+		}
+		raw, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := setFromString(field, raw); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func setFromString(field reflect.Value, raw string) error {
+	if field.Type() == durationType {
+		value, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(value))
+		return nil
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(raw)
+	case reflect.Bool:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		field.SetBool(value)
+	case reflect.Int, reflect.Int64:
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(value)
+	case reflect.Uint64:
+		value, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetUint(value)
+	case reflect.Float64:
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(value)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported slice type %s", field.Type())
+		}
+		var values []string
+		for item := range strings.SplitSeq(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+		field.Set(reflect.ValueOf(values))
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
+
+// secretFileBackend replaces secret values with contents of files set in *_file keys
+type secretFileBackend struct{}
+
+func (b *secretFileBackend) Name() string {
+	return "secret_file"
+}
+
+func (b *secretFileBackend) Get(_ context.Context, _ string) ([]byte, error) {
+	return nil, backend.ErrNotFound
+}
+
+func (b *secretFileBackend) Unmarshal(_ context.Context, to any) error {
+	return walkSecrets(reflect.ValueOf(to).Elem(), func(secret reflect.Value, path string) error {
+		if path == "" {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read secret file: %w", err)
+		}
+		secret.SetString(strings.TrimRight(string(data), "\r\n"))
+		return nil
+	})
+}
+
+// walkSecrets calls fn for every string field having a *_file sibling with the path from that sibling
+func walkSecrets(value reflect.Value, fn func(secret reflect.Value, path string) error) error {
+	valueType := value.Type()
+	fields := make(map[string]reflect.Value)
+	for i := range valueType.NumField() {
+		key := yamlKey(valueType.Field(i))
+		if key == "" {
+			continue
+		}
+		field := value.Field(i)
+		if field.Kind() == reflect.Struct {
+			if err := walkSecrets(field, fn); err != nil {
+				return err
+			}
+			continue
+		}
+		if field.Kind() == reflect.String {
+			fields[key] = field
+		}
+	}
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		secretKey, ok := strings.CutSuffix(key, secretFileSuffix)
+		if !ok {
+			continue
+		}
+		secret, ok := fields[secretKey]
+		if !ok {
+			continue
+		}
+		if err := fn(secret, fields[key].String()); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+// Redacted returns a copy of config with all secret values hidden
+func (c *Config) Redacted() *Config {
+	redacted := *c
+	_ = walkSecrets(reflect.ValueOf(&redacted).Elem(), func(secret reflect.Value, _ string) error {
+		if secret.String() != "" {
+			secret.SetString(redactedValue)
+		}
+		return nil
+	})
+	return &redacted
+}
+
+// Diff returns yaml paths (e.g. valkey.failover_timeout) of values differing between configs
+func Diff(old, updated *Config) []string {
+	var changed []string
+	diffValues(reflect.ValueOf(old).Elem(), reflect.ValueOf(updated).Elem(), "", &changed)
+	return changed
+}
+
+func diffValues(old, updated reflect.Value, prefix string, changed *[]string) {
+	valueType := old.Type()
+	for i := range valueType.NumField() {
+		key := yamlKey(valueType.Field(i))
+		if key == "" {
+			continue
+		}
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		oldField := old.Field(i)
+		updatedField := updated.Field(i)
+		if oldField.Kind() == reflect.Struct {
+			diffValues(oldField, updatedField, key, changed)
+			continue
+		}
+		if !reflect.DeepEqual(oldField.Interface(), updatedField.Interface()) {
+			*changed = append(*changed, key)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ShardKeys are config keys which could be overridden shard-wide via DCS
+var ShardKeys = []string{
+	"aof_mode",
+	"inactivation_delay",
+	"valkey.allow_data_loss",
+	"valkey.candidate_max_memory_usage",
+	"valkey.candidate_scoring",
+	"valkey.failover_backoff",
+	"valkey.failover_budget",
+	"valkey.failover_budget_window",
+	"valkey.failover_cooldown",
+	"valkey.failover_observer_quorum",
+	"valkey.failover_timeout",
+	"valkey.failover_zone_majority",
+	"valkey.fencing_policy",
+	"valkey.join_max_lag",
+	"valkey.max_parallel_syncs",
+	"valkey.replication_quorum_policy",
+	"valkey.replication_quorum_size",
+	"valkey.split_brain_fence_ttl",
+	"valkey.split_brain_policy",
+	"valkey.stale_replica_lag_close",
+	"valkey.stale_replica_lag_open",
+	"valkey.switch_back",
+	"valkey.switch_back_stable_period",
+	"valkey.switch_back_window",
+	"valkey.switchover_failed_cooldown",
+	"valkey.switchover_max_target_lag",
+	"valkey.switchover_preconditions",
+	"valkey.switchover_timeout",
+	"valkey.turn_before_switchover",
+	"valkey.wait_catchup_timeout",
+	"valkey.wait_poison_pill_timeout",
+	"valkey.wait_promote_timeout",
+	"valkey.wait_replication_timeout",
+}
+
+func findValue(value reflect.Value, key string) (reflect.Value, error) {
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		valueType := value.Type()
+		found := false
+		for j := range valueType.NumField() {
+			if yamlKey(valueType.Field(j)) == part {
+				value = value.Field(j)
+				found = true
+				break
+			}
+		}
+		if !found || (i < len(parts)-1 && value.Kind() != reflect.Struct) {
+			return reflect.Value{}, fmt.Errorf("unknown config key %s", key)
+		}
+	}
+	if value.Kind() == reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("config key %s is not a value", key)
+	}
+	return value, nil
+}
+
+// SetValue sets config value by yaml path (e.g. valkey.failover_timeout) from its string representation
+func (c *Config) SetValue(key, raw string) error {
+	value, err := findValue(reflect.ValueOf(c).Elem(), key)
+	if err != nil {
+		return err
+	}
+	if err = setFromString(value, raw); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", key, err)
+	}
+	return nil
+}
+
+// GetValue returns string representation of config value by yaml path
+func (c *Config) GetValue(key string) (string, error) {
+	value, err := findValue(reflect.ValueOf(c).Elem(), key)
+	if err != nil {
+		return "", err
+	}
+	if value.Kind() == reflect.Slice {
+		return strings.Join(value.Interface().([]string), ","), nil
+	}
+	return fmt.Sprint(value.Interface()), nil
+}
+
+// ValidateShardOverride checks that key could be set shard-wide and value is parseable
+func ValidateShardOverride(key, raw string) error {
+	if !slices.Contains(ShardKeys, key) {
+		return fmt.Errorf("%s could not be set shard-wide, supported keys: %s", key, strings.Join(ShardKeys, ", "))
+	}
+	var conf Config
+	return conf.SetValue(key, raw)
+}
+
+// WithShardOverrides returns a copy of config with shard-wide overrides applied.
+// Keys listed in pinned_local_settings keep local values.
+// Invalid overrides are skipped and reported in returned error.
+func (c *Config) WithShardOverrides(overrides map[string]string) (*Config, error) {
+	effective := *c
+	keys := make([]string, 0, len(overrides))
+	for key := range overrides {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	var errs []string
+	for _, key := range keys {
+		if slices.Contains(c.PinnedLocalSettings, key) {
+			continue
+		}
+		if err := ValidateShardOverride(key, overrides[key]); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		if err := effective.SetValue(key, overrides[key]); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return &effective, fmt.Errorf("invalid shard config: %s", strings.Join(errs, "; "))
+	}
+	return &effective, nil
+}
+
+// ShardSettings returns string representation of all shard-wide config values
+func (c *Config) ShardSettings() (map[string]string, error) {
+	settings := make(map[string]string, len(ShardKeys))
+	for _, key := range ShardKeys {
+		value, err := c.GetValue(key)
+		if err != nil {
+			return nil, err
+		}
+		settings[key] = value
+	}
+	return settings, nil
+}
@@ -15,7 +15,9 @@ type ZookeeperConfig struct {
 	CertFile              string                   `config:"certfile" yaml:"certfile"`
 	KeyFile               string                   `config:"keyfile" yaml:"keyfile"`
 	Password              string                   `config:"password" yaml:"password"`
+	PasswordFile          string                   `config:"password_file" yaml:"password_file"`
 	Username              string                   `config:"username" yaml:"username"`
+	UsernameFile          string                   `config:"username_file" yaml:"username_file"`
 	Hosts                 []string                 `config:"hosts,required" yaml:"hosts"`
 	RandomHostProvider    RandomHostProviderConfig `config:"random_host_provider" yaml:"random_host_provider"`
 	BackoffInterval       time.Duration            `config:"backoff_interval" yaml:"backoff_interval"`
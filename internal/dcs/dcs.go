@@ -24,6 +24,8 @@ type DCS interface {
 	Set(path string, value any) error
 	SetEphemeral(path string, value any) error
 	Get(path string, dest any) error
+	GetVersioned(path string, dest any) (int32, error)
+	SetVersioned(path string, value any, version int32) error
 	Delete(path string) error
 	GetTree(path string) (any, error)
 	GetChildren(path string) ([]string, error)
@@ -42,8 +44,47 @@ var (
 	ErrNotFound = errors.New("key was not found in DCS")
 	// ErrMalformed means that we failed to unmarshall received data
 	ErrMalformed = errors.New("failed to parse DCS value, possibly data format changed")
+	// ErrVersionMismatch means that node was changed concurrently since it was read
+	ErrVersionMismatch = errors.New("key was changed concurrently")
 )
 
+// VersionMissing is a version of node which does not exist yet
+const VersionMissing int32 = -1
+
+// maxUpdateAttempts limits retries of Update on concurrent modification
+const maxUpdateAttempts = 10
+
+// Update applies change to value stored at path (zero value if missing)
+// and writes it back only if node was not modified concurrently, retrying otherwise
+func Update[T any](d DCS, path string, update func(value *T) error) error {
+	return versionedUpdate(d, path, true, update)
+}
+
+// UpdateExisting is like Update but returns ErrNotFound instead of creating missing node
+func UpdateExisting[T any](d DCS, path string, update func(value *T) error) error {
+	return versionedUpdate(d, path, false, update)
+}
+
+func versionedUpdate[T any](d DCS, path string, create bool, update func(value *T) error) error {
+	for range maxUpdateAttempts {
+		var value T
+		version, err := d.GetVersioned(path, &value)
+		if errors.Is(err, ErrNotFound) && create {
+			version = VersionMissing
+		} else if err != nil {
+			return err
+		}
+		if err = update(&value); err != nil {
+			return err
+		}
+		err = d.SetVersioned(path, value, version)
+		if !errors.Is(err, ErrVersionMismatch) {
+			return err
+		}
+	}
+	return ErrVersionMismatch
+}
+
 // sep is a path separator for most common DCS
 // Zookeeper, etcd and consul use slash
 const sep = "/"
@@ -511,6 +511,51 @@ func (z *zkDCS) Get(path string, dest any) error {
 	return nil
 }
 
+func (z *zkDCS) GetVersioned(path string, dest any) (int32, error) {
+	fullPath := z.buildFullPath(path)
+	data, stat, err := z.retryGet(fullPath)
+	if errors.Is(err, zk.ErrNoNode) {
+		return VersionMissing, ErrNotFound
+	}
+	if err != nil {
+		z.logger.Error().Err(err).Msgf("Failed to get node %s", fullPath)
+		return VersionMissing, err
+	}
+	if err = json.Unmarshal(data, dest); err != nil {
+		z.logger.Error().Err(err).Msgf("Malformed node data %s (%s)", fullPath, data)
+		return VersionMissing, ErrMalformed
+	}
+	return stat.Version, nil
+}
+
+func (z *zkDCS) SetVersioned(path string, val any, version int32) error {
+	fullPath := z.buildFullPath(path)
+	data, err := json.Marshal(val)
+	if err != nil {
+		return fmt.Errorf("failed to serialize to JSON %#v", val)
+	}
+	if version == VersionMissing {
+		parts := strings.Split(fullPath, sep)
+		err = z.makePath(strings.Join(parts[:len(parts)-1], sep))
+		if err != nil {
+			return err
+		}
+		_, err = z.retryCreate(fullPath, data, 0, z.acl)
+		if errors.Is(err, zk.ErrNodeExists) {
+			return ErrVersionMismatch
+		}
+	} else {
+		_, err = z.retrySet(fullPath, data, version)
+		if errors.Is(err, zk.ErrBadVersion) || errors.Is(err, zk.ErrNoNode) {
+			return ErrVersionMismatch
+		}
+	}
+	if err != nil {
+		z.logger.Error().Err(err).Msgf("Failed to set node %s to %+v", fullPath, val)
+	}
+	return err
+}
+
 func (z *zkDCS) GetTree(path string) (any, error) {
 	fullPath := z.buildFullPath(path)
 	children, err := z.retryChildren(fullPath)
@@ -9,6 +9,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -26,7 +27,7 @@ const (
 type Node struct {
 	ipsTime     time.Time
 	conn        client.Client
-	config      *config.Config
+	config      *config.Holder
 	logger      *zerolog.Logger
 	cachedInfo  map[string]string
 	fqdn        string
@@ -34,6 +35,7 @@ type Node struct {
 	ips         []net.IP
 	infoResults []bool
 	opts        client.ClientOption
+	connLock    sync.Mutex
 }
 
 func uniqLookup(host string) ([]net.IP, error) {
@@ -53,24 +55,7 @@ func uniqLookup(host string) ([]net.IP, error) {
 	return ret, err
 }
 
-// NewNode is a Node constructor
-func NewNode(config *config.Config, logger *zerolog.Logger, fqdn string) (*Node, error) {
-	var host string
-	if fqdn == config.Hostname {
-		// Offline mode forbids connections on non-lo interfaces
-		host = localhost
-	} else {
-		host = fqdn
-	}
-	nl := logger.With().Str("module", "node").Str("fqdn", host).Logger()
-	nodeLogger := &nl
-	now := time.Now()
-	ips, err := uniqLookup(fqdn)
-	if err != nil {
-		nodeLogger.Warn().Err(err).Msg("Dns lookup failed")
-		ips = []net.IP{}
-		now = time.Time{}
-	}
+func nodeClientOptions(config *config.Config, host string) (client.ClientOption, error) {
 	addr := net.JoinHostPort(host, strconv.Itoa(config.Valkey.Port))
 	opts := client.ClientOption{
 		InitAddress:           []string{addr},
@@ -87,16 +72,41 @@ func NewNode(config *config.Config, logger *zerolog.Logger, fqdn string) (*Node,
 	if config.Valkey.UseTLS {
 		tlsConf, err := getTLSConfig(config, config.Valkey.TLSCAPath, host)
 		if err != nil {
-			return nil, err
+			return opts, err
 		}
 		opts.TLSConfig = tlsConf
 	}
+	return opts, nil
+}
+
+// NewNode is a Node constructor
+func NewNode(config *config.Holder, logger *zerolog.Logger, fqdn string) (*Node, error) {
+	var host string
+	if fqdn == config.Get().Hostname {
+		// Offline mode forbids connections on non-lo interfaces
+		host = localhost
+	} else {
+		host = fqdn
+	}
+	nl := logger.With().Str("module", "node").Str("fqdn", host).Logger()
+	nodeLogger := &nl
+	now := time.Now()
+	ips, err := uniqLookup(fqdn)
+	if err != nil {
+		nodeLogger.Warn().Err(err).Msg("Dns lookup failed")
+		ips = []net.IP{}
+		now = time.Time{}
+	}
+	opts, err := nodeClientOptions(config.Get(), host)
+	if err != nil {
+		return nil, err
+	}
 	conn, err := client.NewClient(opts)
 	if err != nil {
 		logger.Warn().Str("fqdn", host).Err(err).Msg("Unable to establish initial connection")
 		conn = nil
 	}
-	node := Node{
+	node := &Node{
 		clusterID: "",
 		config:    config,
 		conn:      conn,
@@ -106,7 +116,7 @@ func NewNode(config *config.Config, logger *zerolog.Logger, fqdn string) (*Node,
 		ipsTime:   now,
 		opts:      opts,
 	}
-	return &node, nil
+	return node, nil
 }
 
 // FQDN returns Node fqdn
@@ -116,7 +126,7 @@ func (n *Node) FQDN() string {
 
 // IsLocal returns true if Node running on the same host as calling rdsync process
 func (n *Node) IsLocal() bool {
-	return n.fqdn == n.config.Hostname
+	return n.fqdn == n.config.Get().Hostname
 }
 
 func (n *Node) String() string {
@@ -125,20 +135,49 @@ func (n *Node) String() string {
 
 // Close closes underlying valkey connection
 func (n *Node) Close() {
+	n.connLock.Lock()
+	defer n.connLock.Unlock()
 	if n.conn != nil {
 		n.conn.Close()
 	}
 }
 
-func (n *Node) ensureConn() error {
+// Reconnect rebuilds connection options from config (credentials, timeouts, tls)
+// and replaces underlying valkey connection keeping ping history intact
+func (n *Node) Reconnect() error {
+	host := n.fqdn
+	if n.IsLocal() {
+		host = localhost
+	}
+	opts, err := nodeClientOptions(n.config.Get(), host)
+	if err != nil {
+		return err
+	}
+	conn, err := client.NewClient(opts)
+	n.connLock.Lock()
+	old := n.conn
+	n.opts = opts
+	n.conn = conn
+	n.connLock.Unlock()
+	// commands running concurrently on old connection fail and are retried by callers on next tick
+	if old != nil {
+		old.Close()
+	}
+	return err
+}
+
+// getConn returns current connection establishing it if needed
+func (n *Node) getConn() (client.Client, error) {
+	n.connLock.Lock()
+	defer n.connLock.Unlock()
 	if n.conn == nil {
 		conn, err := client.NewClient(n.opts)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		n.conn = conn
 	}
-	return nil
+	return n.conn, nil
 }
 
 // MatchHost checks if node has target hostname or ip
@@ -155,7 +194,7 @@ func (n *Node) MatchHost(host string) bool {
 
 // RefreshAddrs updates internal ip address list if ttl exceeded
 func (n *Node) RefreshAddrs() error {
-	if time.Since(n.ipsTime) < n.config.Valkey.DNSTTL {
+	if time.Since(n.ipsTime) < n.config.Get().Valkey.DNSTTL {
 		n.logger.Debug().Msg("Not updating ips cache due to ttl")
 		return nil
 	}
@@ -189,11 +228,11 @@ func (n *Node) GetIPs() []string {
 }
 
 func (n *Node) configRewrite(ctx context.Context) error {
-	err := n.ensureConn()
+	conn, err := n.getConn()
 	if err != nil {
 		return err
 	}
-	return n.conn.Do(ctx, n.conn.B().ConfigRewrite().Build()).Error()
+	return conn.Do(ctx, conn.B().ConfigRewrite().Build()).Error()
 }
 
 func configParse(key string, cmd client.ValkeyResult) (string, error) {
@@ -214,11 +253,11 @@ func configParse(key string, cmd client.ValkeyResult) (string, error) {
 
 // configGet returns str value of config key
 func (n *Node) configGet(ctx context.Context, key string) (string, error) {
-	err := n.ensureConn()
+	conn, err := n.getConn()
 	if err != nil {
 		return "", err
 	}
-	cmd := n.conn.Do(ctx, n.conn.B().ConfigGet().Parameter(key).Build())
+	cmd := conn.Do(ctx, conn.B().ConfigGet().Parameter(key).Build())
 	return configParse(key, cmd)
 }
 
@@ -230,11 +269,11 @@ func (n *Node) IsReplPaused(ctx context.Context) (bool, error) {
 
 // PauseReplication pauses replication from master on node
 func (n *Node) PauseReplication(ctx context.Context) error {
-	err := n.ensureConn()
+	conn, err := n.getConn()
 	if err != nil {
 		return err
 	}
-	cmd := n.conn.Do(ctx, n.conn.B().Arbitrary("CONFIG", "SET", "repl-paused", "yes").Build())
+	cmd := conn.Do(ctx, conn.B().Arbitrary("CONFIG", "SET", "repl-paused", "yes").Build())
 	err = cmd.Error()
 	if err != nil {
 		return err
@@ -244,11 +283,11 @@ func (n *Node) PauseReplication(ctx context.Context) error {
 
 // ResumeReplication starts replication from master on node
 func (n *Node) ResumeReplication(ctx context.Context) error {
-	err := n.ensureConn()
+	conn, err := n.getConn()
 	if err != nil {
 		return err
 	}
-	cmd := n.conn.Do(ctx, n.conn.B().Arbitrary("CONFIG", "SET", "repl-paused", "no").Build())
+	cmd := conn.Do(ctx, conn.B().Arbitrary("CONFIG", "SET", "repl-paused", "no").Build())
 	err = cmd.Error()
 	if err != nil {
 		return err
@@ -256,6 +295,15 @@ func (n *Node) ResumeReplication(ctx context.Context) error {
 	return n.configRewrite(ctx)
 }
 
+// Ping checks that node responds without touching ping history
+func (n *Node) Ping(ctx context.Context) error {
+	conn, err := n.getConn()
+	if err != nil {
+		return err
+	}
+	return conn.Do(ctx, conn.B().Ping().Build()).Error()
+}
+
 // IsOffline returns Offline status for node
 func (n *Node) IsOffline(ctx context.Context) (bool, error) {
 	val, err := n.configGet(ctx, "offline")
@@ -267,11 +315,11 @@ func (n *Node) SetOffline(ctx context.Context) error {
 	if !n.IsLocal() {
 		return fmt.Errorf("making %s offline is not possible - not local", n.fqdn)
 	}
-	err := n.ensureConn()
+	conn, err := n.getConn()
 	if err != nil {
 		return err
 	}
-	cmd := n.conn.Do(ctx, n.conn.B().Arbitrary("CONFIG", "SET", "offline", "yes").Build())
+	cmd := conn.Do(ctx, conn.B().Arbitrary("CONFIG", "SET", "offline", "yes").Build())
 	err = cmd.Error()
 	if err != nil {
 		return err
@@ -288,11 +336,11 @@ func (n *Node) SetOffline(ctx context.Context) error {
 
 // DisconnectClients disconnects all connected clients with specified type
 func (n *Node) DisconnectClients(ctx context.Context, ctype string) error {
-	err := n.ensureConn()
+	conn, err := n.getConn()
 	if err != nil {
 		return err
 	}
-	return n.conn.Do(ctx, n.conn.B().Arbitrary("CLIENT", "KILL", "TYPE", ctype).Build()).Error()
+	return conn.Do(ctx, conn.B().Arbitrary("CLIENT", "KILL", "TYPE", ctype).Build()).Error()
 }
 
 // GetNumQuorumReplicas returns number of connected replicas to accept writes on node
@@ -310,11 +358,11 @@ func (n *Node) GetNumQuorumReplicas(ctx context.Context) (int, error) {
 
 // SetNumQuorumReplicas sets desired number of connected replicas to accept writes on node
 func (n *Node) SetNumQuorumReplicas(ctx context.Context, value int) (error, error) {
-	err := n.ensureConn()
+	conn, err := n.getConn()
 	if err != nil {
 		return err, nil
 	}
-	cmd := n.conn.Do(ctx, n.conn.B().Arbitrary("CONFIG", "SET", "quorum-replicas-to-write", strconv.Itoa(value)).Build())
+	cmd := conn.Do(ctx, conn.B().Arbitrary("CONFIG", "SET", "quorum-replicas-to-write", strconv.Itoa(value)).Build())
 	err = cmd.Error()
 	if err != nil {
 		return err, nil
@@ -335,11 +383,11 @@ func (n *Node) GetQuorumReplicas(ctx context.Context) (string, error) {
 
 // SetQuorumReplicas sets desired quorum replicas
 func (n *Node) SetQuorumReplicas(ctx context.Context, value string) (error, error) {
-	err := n.ensureConn()
+	conn, err := n.getConn()
 	if err != nil {
 		return err, nil
 	}
-	cmd := n.conn.Do(ctx, n.conn.B().Arbitrary("CONFIG", "SET", "quorum-replicas", value).Build())
+	cmd := conn.Do(ctx, conn.B().Arbitrary("CONFIG", "SET", "quorum-replicas", value).Build())
 	err = cmd.Error()
 	if err != nil {
 		return err, nil
@@ -377,11 +425,11 @@ func (n *Node) SetAppendonly(ctx context.Context, value bool) error {
 	if !value {
 		strValue = "no"
 	}
-	err := n.ensureConn()
+	conn, err := n.getConn()
 	if err != nil {
 		return err
 	}
-	err = n.conn.Do(ctx, n.conn.B().Arbitrary("CONFIG", "SET", "appendonly", strValue).Build()).Error()
+	err = conn.Do(ctx, conn.B().Arbitrary("CONFIG", "SET", "appendonly", strValue).Build()).Error()
 	if err != nil {
 		return err
 	}
@@ -390,11 +438,11 @@ func (n *Node) SetAppendonly(ctx context.Context, value bool) error {
 
 // SetReadOnly makes node read-only by setting min replicas to unreasonably high value and disconnecting clients
 func (n *Node) SetReadOnly(ctx context.Context, disconnect bool) (error, error) {
-	err := n.ensureConn()
+	conn, err := n.getConn()
 	if err != nil {
 		return err, nil
 	}
-	err = n.conn.Do(ctx, n.conn.B().Arbitrary("CONFIG", "SET", "min-replicas-to-write", strconv.Itoa(highMinReplicas)).Build()).Error()
+	err = conn.Do(ctx, conn.B().Arbitrary("CONFIG", "SET", "min-replicas-to-write", strconv.Itoa(highMinReplicas)).Build()).Error()
 	if err != nil {
 		return err, nil
 	}
@@ -414,11 +462,11 @@ func (n *Node) SetReadOnly(ctx context.Context, disconnect bool) (error, error)
 
 // SetReadOnly makes node returns min-replicas-to-write to zero
 func (n *Node) SetReadWrite(ctx context.Context) (error, error) {
-	err := n.ensureConn()
+	conn, err := n.getConn()
 	if err != nil {
 		return err, nil
 	}
-	err = n.conn.Do(ctx, n.conn.B().Arbitrary("CONFIG", "SET", "min-replicas-to-write", "0").Build()).Error()
+	err = conn.Do(ctx, conn.B().Arbitrary("CONFIG", "SET", "min-replicas-to-write", "0").Build()).Error()
 	if err != nil {
 		return err, nil
 	}
@@ -430,11 +478,11 @@ func (n *Node) SetOnline(ctx context.Context) error {
 	if !n.IsLocal() {
 		return fmt.Errorf("making %s online is not possible - not local", n.fqdn)
 	}
-	err := n.ensureConn()
+	conn, err := n.getConn()
 	if err != nil {
 		return err
 	}
-	return n.conn.Do(ctx, n.conn.B().Arbitrary("CONFIG", "SET", "offline", "no").Build()).Error()
+	return conn.Do(ctx, conn.B().Arbitrary("CONFIG", "SET", "offline", "no").Build()).Error()
 }
 
 // Restart restarts valkey server
@@ -442,8 +490,8 @@ func (n *Node) Restart(ctx context.Context) error {
 	if !n.IsLocal() {
 		return fmt.Errorf("restarting %s is not possible - not local", n.fqdn)
 	}
-	n.logger.Warn().Msgf("Restarting with %s", n.config.Valkey.RestartCommand)
-	split := strings.Fields(n.config.Valkey.RestartCommand)
+	n.logger.Warn().Msgf("Restarting with %s", n.config.Get().Valkey.RestartCommand)
+	split := strings.Fields(n.config.Get().Valkey.RestartCommand)
 	cmd := exec.CommandContext(ctx, split[0], split[1:]...)
 	return cmd.Run()
 }
@@ -452,21 +500,21 @@ func (n *Node) Restart(ctx context.Context) error {
 func (n *Node) GetState(ctx context.Context) (map[string]string, int64, bool, bool, bool, error) {
 	var err error
 	var resps []client.ValkeyResult
-	err = n.ensureConn()
+	conn, err := n.getConn()
 	if err == nil {
-		resps = n.conn.DoMulti(
+		resps = conn.DoMulti(
 			ctx,
-			n.conn.B().Ping().Build(),
-			n.conn.B().Info().Build(),
-			n.conn.B().ConfigGet().Parameter("min-replicas-to-write").Build(),
-			n.conn.B().ConfigGet().Parameter("offline").Build(),
-			n.conn.B().ConfigGet().Parameter("repl-paused").Build(),
+			conn.B().Ping().Build(),
+			conn.B().Info().Build(),
+			conn.B().ConfigGet().Parameter("min-replicas-to-write").Build(),
+			conn.B().ConfigGet().Parameter("offline").Build(),
+			conn.B().ConfigGet().Parameter("repl-paused").Build(),
 		)
 		err = resps[0].Error()
 	}
 	if err != nil {
 		n.infoResults = append(n.infoResults, false)
-		if len(n.infoResults) > n.config.PingStable {
+		if len(n.infoResults) > n.config.Get().PingStable {
 			n.infoResults = n.infoResults[1:]
 		}
 		clearCache := true
@@ -503,7 +551,7 @@ func (n *Node) GetState(ctx context.Context) (map[string]string, int64, bool, bo
 		res[before] = after
 	}
 	n.infoResults = append(n.infoResults, true)
-	if len(n.infoResults) > n.config.PingStable {
+	if len(n.infoResults) > n.config.Get().PingStable {
 		n.infoResults = n.infoResults[1:]
 	}
 	n.cachedInfo = res
@@ -550,7 +598,11 @@ func (n *Node) SentinelMakeReplica(ctx context.Context, target string) error {
 	if err != nil {
 		return err
 	}
-	err = n.conn.Do(ctx, n.conn.B().Replicaof().Host(target).Port(int64(n.config.Valkey.Port)).Build()).Error()
+	conn, err := n.getConn()
+	if err != nil {
+		return err
+	}
+	err = conn.Do(ctx, conn.B().Replicaof().Host(target).Port(int64(n.config.Get().Valkey.Port)).Build()).Error()
 	if err != nil {
 		return err
 	}
@@ -559,11 +611,11 @@ func (n *Node) SentinelMakeReplica(ctx context.Context, target string) error {
 
 // SentinelPromote makes node primary in sentinel mode
 func (n *Node) SentinelPromote(ctx context.Context) error {
-	err := n.ensureConn()
+	conn, err := n.getConn()
 	if err != nil {
 		return err
 	}
-	err = n.conn.Do(ctx, n.conn.B().Replicaof().No().One().Build()).Error()
+	err = conn.Do(ctx, conn.B().Replicaof().No().One().Build()).Error()
 	if err != nil {
 		return err
 	}
@@ -575,11 +627,11 @@ func (n *Node) ClusterGetID(ctx context.Context) (string, error) {
 	if n.clusterID != "" {
 		return n.clusterID, nil
 	}
-	err := n.ensureConn()
+	conn, err := n.getConn()
 	if err != nil {
 		return "", err
 	}
-	cmd := n.conn.Do(ctx, n.conn.B().ClusterMyid().Build())
+	cmd := conn.Do(ctx, conn.B().ClusterMyid().Build())
 	err = cmd.Error()
 	if err != nil {
 		return "", err
@@ -594,16 +646,20 @@ func (n *Node) ClusterMakeReplica(ctx context.Context, targetID string) error {
 	if err != nil {
 		return err
 	}
-	return n.conn.Do(ctx, n.conn.B().ClusterReplicate().NodeId(targetID).Build()).Error()
+	conn, err := n.getConn()
+	if err != nil {
+		return err
+	}
+	return conn.Do(ctx, conn.B().ClusterReplicate().NodeId(targetID).Build()).Error()
 }
 
 // IsClusterMajorityAlive checks if majority of masters in cluster are not failed
 func (n *Node) IsClusterMajorityAlive(ctx context.Context) (bool, error) {
-	err := n.ensureConn()
+	conn, err := n.getConn()
 	if err != nil {
 		return false, err
 	}
-	cmd := n.conn.Do(ctx, n.conn.B().ClusterNodes().Build())
+	cmd := conn.Do(ctx, conn.B().ClusterNodes().Build())
 	err = cmd.Error()
 	if err != nil {
 		return false, err
@@ -633,29 +689,29 @@ func (n *Node) IsClusterMajorityAlive(ctx context.Context) (bool, error) {
 
 // ClusterPromoteForce makes node primary in cluster mode if master/majority of masters is reachable
 func (n *Node) ClusterPromoteForce(ctx context.Context) error {
-	err := n.ensureConn()
+	conn, err := n.getConn()
 	if err != nil {
 		return err
 	}
-	return n.conn.Do(ctx, n.conn.B().ClusterFailover().Force().Build()).Error()
+	return conn.Do(ctx, conn.B().ClusterFailover().Force().Build()).Error()
 }
 
 // ClusterPromoteTakeover makes node primary in cluster mode if majority of masters is not reachable
 func (n *Node) ClusterPromoteTakeover(ctx context.Context) error {
-	err := n.ensureConn()
+	conn, err := n.getConn()
 	if err != nil {
 		return err
 	}
-	return n.conn.Do(ctx, n.conn.B().ClusterFailover().Takeover().Build()).Error()
+	return conn.Do(ctx, conn.B().ClusterFailover().Takeover().Build()).Error()
 }
 
 // IsClusterNodeAlone checks if node sees only itself
 func (n *Node) IsClusterNodeAlone(ctx context.Context) (bool, error) {
-	err := n.ensureConn()
+	conn, err := n.getConn()
 	if err != nil {
 		return false, err
 	}
-	cmd := n.conn.Do(ctx, n.conn.B().ClusterNodes().Build())
+	cmd := conn.Do(ctx, conn.B().ClusterNodes().Build())
 	err = cmd.Error()
 	if err != nil {
 		return false, err
@@ -676,20 +732,20 @@ func (n *Node) IsClusterNodeAlone(ctx context.Context) (bool, error) {
 
 // ClusterMeet makes replica join the cluster
 func (n *Node) ClusterMeet(ctx context.Context, addr string, port, clusterBusPort int) error {
-	err := n.ensureConn()
+	conn, err := n.getConn()
 	if err != nil {
 		return err
 	}
-	return n.conn.Do(ctx, n.conn.B().ClusterMeet().Ip(addr).Port(int64(port)).ClusterBusPort(int64(clusterBusPort)).Build()).Error()
+	return conn.Do(ctx, conn.B().ClusterMeet().Ip(addr).Port(int64(port)).ClusterBusPort(int64(clusterBusPort)).Build()).Error()
 }
 
 // HasClusterSlots checks if node has any slot assigned
 func (n *Node) HasClusterSlots(ctx context.Context) (bool, error) {
-	err := n.ensureConn()
+	conn, err := n.getConn()
 	if err != nil {
 		return false, err
 	}
-	cmd := n.conn.Do(ctx, n.conn.B().ClusterNodes().Build())
+	cmd := conn.Do(ctx, conn.B().ClusterNodes().Build())
 	err = cmd.Error()
 	if err != nil {
 		return false, err
@@ -712,9 +768,9 @@ func (n *Node) HasClusterSlots(ctx context.Context) (bool, error) {
 
 // ScriptKill kills a running script if node is in BUSY state
 func (n *Node) ScriptKill(ctx context.Context) error {
-	err := n.ensureConn()
+	conn, err := n.getConn()
 	if err != nil {
 		return err
 	}
-	return n.conn.Do(ctx, n.conn.B().ScriptKill().Build()).Error()
+	return conn.Do(ctx, conn.B().ScriptKill().Build()).Error()
 }
@@ -7,6 +7,7 @@ import (
 	"os/exec"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog"
@@ -57,15 +58,16 @@ type SentiCacheState struct {
 
 // SentiCacheNode represents API to query/manipulate a single Valkey SentiCache node
 type SentiCacheNode struct {
-	config *config.Config
-	logger *zerolog.Logger
-	conn   client.Client
-	opts   client.ClientOption
-	broken bool
+	config   *config.Holder
+	logger   *zerolog.Logger
+	conn     client.Client
+	host     string
+	opts     client.ClientOption
+	connLock sync.Mutex
+	broken   bool
 }
 
-// NewRemoteSentiCacheNode is a remote SentiCacheNode constructor
-func NewRemoteSentiCacheNode(config *config.Config, host string, logger *zerolog.Logger) (*SentiCacheNode, error) {
+func sentiCacheClientOptions(config *config.Config, host string) (client.ClientOption, error) {
 	addr := net.JoinHostPort(host, strconv.Itoa(config.SentinelMode.CachePort))
 	opts := client.ClientOption{
 		InitAddress:           []string{addr},
@@ -82,10 +84,19 @@ func NewRemoteSentiCacheNode(config *config.Config, host string, logger *zerolog
 	if config.SentinelMode.UseTLS {
 		tlsConf, err := getTLSConfig(config, config.SentinelMode.TLSCAPath, host)
 		if err != nil {
-			return nil, err
+			return opts, err
 		}
 		opts.TLSConfig = tlsConf
 	}
+	return opts, nil
+}
+
+// NewRemoteSentiCacheNode is a remote SentiCacheNode constructor
+func NewRemoteSentiCacheNode(config *config.Holder, host string, logger *zerolog.Logger) (*SentiCacheNode, error) {
+	opts, err := sentiCacheClientOptions(config.Get(), host)
+	if err != nil {
+		return nil, err
+	}
 	conn, err := client.NewClient(opts)
 	if err != nil {
 		logger.Warn().Str("fqdn", host).Err(err).Msg("Unable to establish initial connection")
@@ -96,6 +107,7 @@ func NewRemoteSentiCacheNode(config *config.Config, host string, logger *zerolog
 		config: config,
 		conn:   conn,
 		opts:   opts,
+		host:   host,
 		logger: &sl,
 		broken: false,
 	}
@@ -103,41 +115,64 @@ func NewRemoteSentiCacheNode(config *config.Config, host string, logger *zerolog
 }
 
 // NewSentiCacheNode is a local SentiCacheNode constructor
-func NewSentiCacheNode(config *config.Config, logger *zerolog.Logger) (*SentiCacheNode, error) {
+func NewSentiCacheNode(config *config.Holder, logger *zerolog.Logger) (*SentiCacheNode, error) {
 	return NewRemoteSentiCacheNode(config, localhost, logger)
 }
 
 // Close closes underlying Valkey connection
 func (s *SentiCacheNode) Close() {
+	s.connLock.Lock()
+	defer s.connLock.Unlock()
 	if s.conn != nil {
 		s.conn.Close()
 	}
 }
 
-func (s *SentiCacheNode) ensureConn() error {
+// Reconnect rebuilds connection options from config and replaces underlying connection
+func (s *SentiCacheNode) Reconnect() error {
+	opts, err := sentiCacheClientOptions(s.config.Get(), s.host)
+	if err != nil {
+		return err
+	}
+	conn, err := client.NewClient(opts)
+	s.connLock.Lock()
+	old := s.conn
+	s.opts = opts
+	s.conn = conn
+	s.connLock.Unlock()
+	if old != nil {
+		old.Close()
+	}
+	return err
+}
+
+// getConn returns current connection establishing it if needed
+func (s *SentiCacheNode) getConn() (client.Client, error) {
+	s.connLock.Lock()
+	defer s.connLock.Unlock()
 	if s.conn == nil {
 		conn, err := client.NewClient(s.opts)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		s.conn = conn
 	}
-	return nil
+	return s.conn, nil
 }
 
 func (s *SentiCacheNode) restart(ctx context.Context) error {
 	s.logger.Error().Msg("Restarting broken senticache")
-	split := strings.Fields(s.config.SentinelMode.CacheRestartCommand)
+	split := strings.Fields(s.config.Get().SentinelMode.CacheRestartCommand)
 	cmd := exec.CommandContext(ctx, split[0], split[1:]...)
 	return cmd.Run()
 }
 
 func (s *SentiCacheNode) sentinels(ctx context.Context) ([]SentiCacheSentinel, error) {
-	err := s.ensureConn()
+	conn, err := s.getConn()
 	if err != nil {
 		return []SentiCacheSentinel{}, err
 	}
-	cmd := s.conn.Do(ctx, s.conn.B().SentinelSentinels().Master("1").Build())
+	cmd := conn.Do(ctx, conn.B().SentinelSentinels().Master("1").Build())
 	err = cmd.Error()
 	if err != nil {
 		return []SentiCacheSentinel{}, err
@@ -174,11 +209,11 @@ func (s *SentiCacheNode) sentinels(ctx context.Context) ([]SentiCacheSentinel, e
 }
 
 func (s *SentiCacheNode) master(ctx context.Context) (*SentiCacheMaster, error) {
-	err := s.ensureConn()
+	conn, err := s.getConn()
 	if err != nil {
 		return nil, err
 	}
-	cmd := s.conn.Do(ctx, s.conn.B().Arbitrary("SENTINEL", "MASTERS").Build())
+	cmd := conn.Do(ctx, conn.B().Arbitrary("SENTINEL", "MASTERS").Build())
 	err = cmd.Error()
 	if err != nil {
 		return nil, err
@@ -233,11 +268,11 @@ func (s *SentiCacheNode) master(ctx context.Context) (*SentiCacheMaster, error)
 }
 
 func (s *SentiCacheNode) replicas(ctx context.Context) ([]SentiCacheReplica, error) {
-	err := s.ensureConn()
+	conn, err := s.getConn()
 	if err != nil {
 		return []SentiCacheReplica{}, err
 	}
-	cmd := s.conn.Do(ctx, s.conn.B().SentinelReplicas().Master("1").Build())
+	cmd := conn.Do(ctx, conn.B().SentinelReplicas().Master("1").Build())
 	err = cmd.Error()
 	if err != nil {
 		return []SentiCacheReplica{}, err
@@ -334,7 +369,7 @@ func (s *SentiCacheNode) Update(ctx context.Context, state *SentiCacheState) err
 	}
 	s.logger.Debug().Msgf("Previous state: master: %v, replicas: %v, sentinels: %v", master, replicas, sentinels)
 	var command = []string{
-		"SENTINEL", "CACHE-UPDATE", s.config.SentinelMode.CacheUpdateSecret,
+		"SENTINEL", "CACHE-UPDATE", s.config.Get().SentinelMode.CacheUpdateSecret,
 		"master-name:", state.Master.Name + ",",
 		"master-addr:", state.Master.IP, fmt.Sprintf("%d,", state.Master.Port),
 		"master-spec:", state.Master.RunID, fmt.Sprintf("%d", state.Master.Quorum),
@@ -383,7 +418,11 @@ func (s *SentiCacheNode) Update(ctx context.Context, state *SentiCacheState) err
 		)
 	}
 	s.logger.Debug().Msgf("Updating senticache state with %v", command)
-	err = s.conn.Do(ctx, s.conn.B().Arbitrary(command...).Build()).Error()
+	conn, err := s.getConn()
+	if err != nil {
+		return err
+	}
+	err = conn.Do(ctx, conn.B().Arbitrary(command...).Build()).Error()
 	if err != nil {
 		s.broken = true
 		return err
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/rs/zerolog"
 
@@ -15,7 +16,7 @@ import (
 // Shard contains a set of valkey nodes
 type Shard struct {
 	dcs    dcs.DCS
-	config *config.Config
+	config *config.Holder
 	logger *zerolog.Logger
 	nodes  map[string]*Node
 	local  *Node
@@ -24,12 +25,33 @@ type Shard struct {
 
 // NodeConfiguration is a dcs node configuration for valkey replica
 type NodeConfiguration struct {
+	// Maintenance - presence means that host is under manual control: not repaired, not promoted
+	// and not counted in quorum. Can be changed via CLI.
+	Maintenance *HostMaintenance `json:"maintenance,omitempty" yaml:"maintenance,omitempty"`
+	// Zone - availability zone of host. Used in quorum and candidate selection. Can be changed via CLI.
+	Zone string `json:"zone,omitempty" yaml:"zone,omitempty"`
 	// Priority - is a host priority to become master. Can be changed via CLI.
-	Priority int `json:"priority"`
+	Priority int `json:"priority" yaml:"priority"`
+	// Drained - host is taken out of service: not promoted and not kept in active nodes. Can be changed via CLI.
+	Drained bool `json:"drained,omitempty" yaml:"drained,omitempty"`
+	// Joining - host is attached as replica but is not eligible for active nodes and promotion
+	// until it finishes sync. Cleared by manager.
+	Joining bool `json:"joining,omitempty" yaml:"joining,omitempty"`
+	// NeverPromote - host could not become master (e.g. backup or analytics replica). Can be changed via CLI.
+	NeverPromote bool `json:"never_promote,omitempty" yaml:"never_promote,omitempty"`
+	// NonVoting - host is not counted in replicas to write and failover quorum. Can be changed via CLI.
+	NonVoting bool `json:"non_voting,omitempty" yaml:"non_voting,omitempty"`
+}
+
+// HostMaintenance describes per-host maintenance
+type HostMaintenance struct {
+	InitiatedAt time.Time `json:"initiated_at" yaml:"initiated_at"`
+	InitiatedBy string    `json:"initiated_by" yaml:"initiated_by"`
+	Reason      string    `json:"reason" yaml:"reason"`
 }
 
 // NewShard is a Shard constructor
-func NewShard(config *config.Config, logger *zerolog.Logger, dcs dcs.DCS) *Shard {
+func NewShard(config *config.Holder, logger *zerolog.Logger, dcs dcs.DCS) *Shard {
 	sl := logger.With().Str("module", "shard").Logger()
 	s := &Shard{
 		config: config,
@@ -101,6 +123,20 @@ func (s *Shard) UpdateHostsInfo() error {
 	return nil
 }
 
+// Reconnect reopens connections to all nodes using current config
+func (s *Shard) Reconnect() error {
+	s.Lock()
+	defer s.Unlock()
+
+	var errs []error
+	for host, node := range s.nodes {
+		if err := node.Reconnect(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", host, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // Get returns Valkey Node by host name
 func (s *Shard) Get(host string) *Node {
 	s.Lock()